@@ -0,0 +1,417 @@
+package marketo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaximumActivityTypes is the largest number of activity types which
+	// can be requested in a single call.
+	MaximumActivityTypes = 10
+	// MaximumActivityLeads is the largest number of leads activities can be
+	// requested for in a single call.
+	MaximumActivityLeads = 30
+
+	// ActivityTypeNewLead is the activity recorded when a lead is created
+	ActivityTypeNewLead = 12
+	// ActivityTypeChangeDataValue is the activity recorded when a lead
+	// field is updated
+	ActivityTypeChangeDataValue = 13
+)
+
+// Activity is a single lead activity
+type Activity struct {
+	ID                      int                 `json:"id"`
+	MarketoGUID             string              `json:"marketoGUID"`
+	LeadID                  int                 `json:"leadId"`
+	ActivityDate            time.Time           `json:"activityDate"`
+	ActivityTypeID          int                 `json:"activityTypeId"`
+	CampaignID              int                 `json:"campaignId,omitempty"`
+	PrimaryAttributeValueID int                 `json:"primaryAttributeValueId,omitempty"`
+	PrimaryAttributeValue   string              `json:"primaryAttributeValue,omitempty"`
+	Attributes              []ActivityAttribute `json:"attributes,omitempty"`
+}
+
+// ActivityAttribute is a secondary attribute of an activity
+type ActivityAttribute struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// ActivityType describes a type of activity
+type ActivityType struct {
+	ID               int                     `json:"id"`
+	Name             string                  `json:"name"`
+	Description      string                  `json:"description,omitempty"`
+	PrimaryAttribute *ActivityTypeAttribute  `json:"primaryAttribute,omitempty"`
+	Attributes       []ActivityTypeAttribute `json:"attributes,omitempty"`
+}
+
+// ActivityTypeAttribute describes an attribute of an activity type
+type ActivityTypeAttribute struct {
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+}
+
+// CustomActivityType describes a custom activity type, including the
+// attributes which activities of the type may have
+type CustomActivityType struct {
+	ID               int                           `json:"id"`
+	APIName          string                        `json:"apiName"`
+	Name             string                        `json:"name"`
+	Description      string                        `json:"description,omitempty"`
+	TriggerName      string                        `json:"triggerName,omitempty"`
+	FilterName       string                        `json:"filterName,omitempty"`
+	Status           string                        `json:"status,omitempty"`
+	PrimaryAttribute *CustomActivityTypeAttribute  `json:"primaryAttribute,omitempty"`
+	Attributes       []CustomActivityTypeAttribute `json:"attributes,omitempty"`
+	CreatedAt        *time.Time                    `json:"createdAt,omitempty"`
+	UpdatedAt        *time.Time                    `json:"updatedAt,omitempty"`
+}
+
+// CustomActivityTypeAttribute describes an attribute of a custom activity
+// type
+type CustomActivityTypeAttribute struct {
+	APIName     string `json:"apiName"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	DataType    string `json:"dataType"`
+}
+
+// CustomActivity is a custom activity to add to a lead
+type CustomActivity struct {
+	LeadID                int
+	ActivityTypeID        int
+	ActivityDate          time.Time
+	PrimaryAttributeValue string
+	Attributes            map[string]interface{}
+}
+
+// ActivityResult contains the result of adding a single custom activity
+type ActivityResult struct {
+	ID          int      `json:"id,omitempty"`
+	MarketoGUID string   `json:"marketoGUID,omitempty"`
+	Status      string   `json:"status"`
+	Reasons     []Reason `json:"reasons,omitempty"`
+}
+
+// ImportedLeadIDs contains the IDs of leads created or updated
+type ImportedLeadIDs struct {
+	Created []int
+	Updated []int
+}
+
+const (
+	activityPagingToken = "get activity paging token"
+	getActivities       = "get activities"
+	getActivityTypes    = "get activity types"
+	getCustomTypes      = "get custom activity types"
+	addCustomActivities = "add custom activities"
+)
+
+// ActivitiesAPI provides access to the Marketo lead activities API
+type ActivitiesAPI struct {
+	c *Client
+
+	typesLock sync.Mutex
+	types     []ActivityType
+}
+
+// NewActivitiesAPI returns a new instance of the activities API, configured
+// with the provided Client.
+func NewActivitiesAPI(c *Client) *ActivitiesAPI {
+	return &ActivitiesAPI{c: c}
+}
+
+// Types returns the activity types defined in the instance. The types are
+// fetched once and cached for the lifetime of the API.
+func (a *ActivitiesAPI) Types(ctx context.Context) ([]ActivityType, error) {
+	a.typesLock.Lock()
+	defer a.typesLock.Unlock()
+	if a.types != nil {
+		return a.types, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		a.c.url("rest", "v1", "activities", "types.json"),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []ActivityType{}
+	_, err = a.c.doResult(request, getActivityTypes, &types)
+	if err != nil {
+		return nil, err
+	}
+	a.types = types
+	return types, nil
+}
+
+// CustomTypes returns the custom activity types defined in the instance,
+// describing the attributes which may be set when adding custom activities.
+// Unlike Types, the result is not cached.
+func (a *ActivitiesAPI) CustomTypes(ctx context.Context) ([]CustomActivityType, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		a.c.url("rest", "v1", "activities", "external", "types.json"),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []CustomActivityType{}
+	_, err = a.c.doResult(request, getCustomTypes, &types)
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// TypeID returns the ID of the activity type with the provided name, such as
+// "Fill Out Form". IDs vary between instances, so looking them up by name
+// avoids hard-coding them.
+func (a *ActivitiesAPI) TypeID(ctx context.Context, name string) (int, error) {
+	types, err := a.Types(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range types {
+		if t.Name == name {
+			return t.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown activity type %q", name)
+}
+
+// PagingToken returns a paging token which can be passed to Get to retrieve
+// activities which occurred on or after since.
+func (a *ActivitiesAPI) PagingToken(ctx context.Context, since time.Time) (string, error) {
+	query := url.Values{}
+	query.Set("sinceDatetime", since.Format(time.RFC3339))
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		a.c.url("rest", "v1", "activities", "pagingtoken.json?"+query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := a.c.doResult(request, activityPagingToken, nil)
+	if err != nil {
+		return "", err
+	}
+	return response.NextPageToken, nil
+}
+
+// Get returns a page of activities of the provided types, starting at the
+// paging token. If leadIDs are provided, only activities for those leads are
+// returned. The token for the next page is returned, and is empty once there
+// are no more results.
+func (a *ActivitiesAPI) Get(ctx context.Context, pageToken string, typeIDs []int, leadIDs []int) ([]Activity, string, error) {
+	if len(typeIDs) == 0 {
+		return nil, "", errors.New("too few activity types")
+	}
+	if len(typeIDs) > MaximumActivityTypes {
+		return nil, "", errors.New("too many activity types")
+	}
+	if len(leadIDs) > MaximumActivityLeads {
+		return nil, "", errors.New("too many leads")
+	}
+
+	query := url.Values{}
+	query.Set("nextPageToken", pageToken)
+	query.Set("activityTypeIds", joinInts(typeIDs))
+	if len(leadIDs) > 0 {
+		query.Set("leadIds", joinInts(leadIDs))
+	}
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		a.c.url("rest", "v1", "activities.json?"+query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	activities := []Activity{}
+	response, err := a.c.doResult(request, getActivities, &activities)
+	if err != nil {
+		return nil, "", err
+	}
+	if !response.MoreResult {
+		return activities, "", nil
+	}
+	return activities, response.NextPageToken, nil
+}
+
+// GetForLeads returns the activities of the provided types for a set of
+// leads which occurred on or after since. Leads are queried in batches of
+// MaximumActivityLeads, and every page of results is returned.
+func (a *ActivitiesAPI) GetForLeads(ctx context.Context, leadIDs []int, typeIDs []int, since time.Time) ([]Activity, error) {
+	start, err := a.PagingToken(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Activity{}
+	for i := 0; i < len(leadIDs); i += MaximumActivityLeads {
+		end := i + MaximumActivityLeads
+		if end > len(leadIDs) {
+			end = len(leadIDs)
+		}
+
+		for token := start; token != ""; {
+			var activities []Activity
+			activities, token, err = a.Get(ctx, token, typeIDs, leadIDs[i:end])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, activities...)
+		}
+	}
+	return result, nil
+}
+
+// AddCustom adds custom activities to leads, returning the result for each
+// in the order provided. At most MaximumQueryBatchSize activities may be
+// added in a single call.
+func (a *ActivitiesAPI) AddCustom(ctx context.Context, activities []CustomActivity) ([]ActivityResult, error) {
+	if len(activities) > MaximumQueryBatchSize {
+		return nil, fmt.Errorf("too many activities: %d exceeds the maximum of %d",
+			len(activities), MaximumQueryBatchSize)
+	}
+
+	type activity struct {
+		LeadID                int                 `json:"leadId"`
+		ActivityDate          string              `json:"activityDate"`
+		ActivityTypeID        int                 `json:"activityTypeId"`
+		PrimaryAttributeValue string              `json:"primaryAttributeValue"`
+		Attributes            []ActivityAttribute `json:"attributes,omitempty"`
+	}
+	input := struct {
+		Input []activity `json:"input"`
+	}{}
+	for _, ca := range activities {
+		names := make([]string, 0, len(ca.Attributes))
+		for name := range ca.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		attributes := make([]ActivityAttribute, len(names))
+		for i, name := range names {
+			attributes[i] = ActivityAttribute{Name: name, Value: ca.Attributes[name]}
+		}
+		input.Input = append(input.Input, activity{
+			LeadID:                ca.LeadID,
+			ActivityDate:          ca.ActivityDate.Format(time.RFC3339),
+			ActivityTypeID:        ca.ActivityTypeID,
+			PrimaryAttributeValue: ca.PrimaryAttributeValue,
+			Attributes:            attributes,
+		})
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		a.c.url("rest", "v1", "activities", "external.json"),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", "application/json")
+
+	results := []ActivityResult{}
+	_, err = a.c.doResult(request, addCustomActivities, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ImportedLeads returns the leads created or updated between start and end,
+// typically the times an import was created and completed. Marketo does not
+// report which rows of an import created or updated leads, so this relies
+// on the New Lead and Change Data Value activities recorded in that window;
+// changes made by other sources during the import are included as well.
+// Leads which were both created and updated are reported as created.
+func (a *ActivitiesAPI) ImportedLeads(ctx context.Context, start, end time.Time) (*ImportedLeadIDs, error) {
+	token, err := a.PagingToken(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+
+	created := map[int]bool{}
+	updated := map[int]bool{}
+	result := &ImportedLeadIDs{}
+	for token != "" {
+		var activities []Activity
+		activities, token, err = a.Get(ctx, token,
+			[]int{ActivityTypeNewLead, ActivityTypeChangeDataValue}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, activity := range activities {
+			if activity.ActivityDate.After(end) {
+				// activities are returned in order, so nothing after this
+				// is in the window
+				token = ""
+				break
+			}
+			switch activity.ActivityTypeID {
+			case ActivityTypeNewLead:
+				if !created[activity.LeadID] {
+					created[activity.LeadID] = true
+					result.Created = append(result.Created, activity.LeadID)
+				}
+			case ActivityTypeChangeDataValue:
+				if !updated[activity.LeadID] {
+					updated[activity.LeadID] = true
+					result.Updated = append(result.Updated, activity.LeadID)
+				}
+			}
+		}
+	}
+
+	// leads created in the window will usually have field changes recorded
+	// as well
+	updatedOnly := result.Updated[:0]
+	for _, id := range result.Updated {
+		if !created[id] {
+			updatedOnly = append(updatedOnly, id)
+		}
+	}
+	result.Updated = updatedOnly
+
+	return result, nil
+}
+
+// joinInts returns the ints joined with commas
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
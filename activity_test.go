@@ -0,0 +1,262 @@
+package marketo
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestImportedLeads(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/activities/pagingtoken.json").
+		MatchParam("sinceDatetime", "2021-06-01T10:00:00Z").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"TOKEN1"}`)
+	gock.New(testHost).
+		Get("/rest/v1/activities.json").
+		MatchParam("nextPageToken", "TOKEN1").
+		MatchParam("activityTypeIds", "12,13").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"nextPageToken": "TOKEN2",
+			"moreResult": true,
+			"result": [
+				{"id": 1, "leadId": 100, "activityDate": "2021-06-01T10:01:00Z", "activityTypeId": 12},
+				{"id": 2, "leadId": 100, "activityDate": "2021-06-01T10:01:00Z", "activityTypeId": 13},
+				{"id": 3, "leadId": 101, "activityDate": "2021-06-01T10:02:00Z", "activityTypeId": 13}
+			]
+		}`)
+	gock.New(testHost).
+		Get("/rest/v1/activities.json").
+		MatchParam("nextPageToken", "TOKEN2").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"nextPageToken": "TOKEN3",
+			"moreResult": true,
+			"result": [
+				{"id": 4, "leadId": 101, "activityDate": "2021-06-01T10:03:00Z", "activityTypeId": 13},
+				{"id": 5, "leadId": 102, "activityDate": "2021-06-01T11:00:00Z", "activityTypeId": 12}
+			]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	api := NewActivitiesAPI(client)
+	leads, err := api.ImportedLeads(context.Background(), start, start.Add(5*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []int{100}, leads.Created)
+	assert.Equal(t, []int{101}, leads.Updated)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestGetActivitiesForLeads(t *testing.T) {
+	defer gock.Off()
+
+	leadIDs := make([]int, MaximumActivityLeads+1)
+	for i := range leadIDs {
+		leadIDs[i] = i + 1
+	}
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/activities/pagingtoken.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"START"}`)
+	gock.New(testHost).
+		Get("/rest/v1/activities.json").
+		MatchParam("nextPageToken", "START").
+		MatchParam("activityTypeIds", "2").
+		MatchParam("leadIds", "^1,2,").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"NEXT","moreResult":true,"result":[{"id":1,"leadId":1,"activityTypeId":2}]}`)
+	gock.New(testHost).
+		Get("/rest/v1/activities.json").
+		MatchParam("nextPageToken", "NEXT").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"END","moreResult":false,"result":[{"id":2,"leadId":2,"activityTypeId":2}]}`)
+	gock.New(testHost).
+		Get("/rest/v1/activities.json").
+		MatchParam("nextPageToken", "START").
+		MatchParam("leadIds", "^31$").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"END","moreResult":false,"result":[{"id":3,"leadId":31,"activityTypeId":2}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewActivitiesAPI(client)
+	activities, err := api.GetForLeads(context.Background(), leadIDs, []int{2}, time.Now())
+	require.NoError(t, err)
+	require.Len(t, activities, 3)
+	assert.Equal(t, 31, activities[2].LeadID)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestActivityTypeID(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	// the types are only fetched once
+	gock.New(testHost).
+		Get("/rest/v1/activities/types.json").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"result": [
+				{"id": 1, "name": "Visit Webpage", "primaryAttribute": {"name": "Webpage ID", "dataType": "integer"}},
+				{"id": 2, "name": "Fill Out Form", "primaryAttribute": {"name": "Webform ID", "dataType": "integer"}}
+			]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewActivitiesAPI(client)
+	id, err := api.TypeID(context.Background(), "Fill Out Form")
+	require.NoError(t, err)
+	assert.Equal(t, 2, id)
+
+	_, err = api.TypeID(context.Background(), "Unknown")
+	assert.EqualError(t, err, `unknown activity type "Unknown"`)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomActivityTypes(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/activities/external/types.json").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"result": [
+				{
+					"id": 100001,
+					"apiName": "purchase_c",
+					"name": "Purchase",
+					"status": "approved",
+					"primaryAttribute": {"apiName": "product_c", "name": "Product", "dataType": "string"},
+					"attributes": [
+						{"apiName": "price_c", "name": "Price", "dataType": "currency"},
+						{"apiName": "quantity_c", "name": "Quantity", "dataType": "integer"}
+					]
+				}
+			]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+	})
+	require.NoError(t, err)
+
+	types, err := NewActivitiesAPI(client).CustomTypes(context.Background())
+	require.NoError(t, err)
+	require.Len(t, types, 1)
+	assert.Equal(t, 100001, types[0].ID)
+	assert.Equal(t, "purchase_c", types[0].APIName)
+	require.NotNil(t, types[0].PrimaryAttribute)
+	assert.Equal(t, "product_c", types[0].PrimaryAttribute.APIName)
+	require.Len(t, types[0].Attributes, 2)
+	assert.Equal(t, "currency", types[0].Attributes[0].DataType)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestAddCustomActivities(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/activities/external.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"input":[{
+				"leadId": 1001,
+				"activityDate": "2021-06-01T10:00:00Z",
+				"activityTypeId": 100001,
+				"primaryAttributeValue": "Sneakers",
+				"attributes": [
+					{"name": "Price", "value": 59.99},
+					{"name": "Size", "value": "10"}
+				]
+			}]}`, string(body))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"marketoGUID":"abc","status":"added"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewActivitiesAPI(client)
+	results, err := api.AddCustom(context.Background(), []CustomActivity{{
+		LeadID:                1001,
+		ActivityTypeID:        100001,
+		ActivityDate:          time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+		PrimaryAttributeValue: "Sneakers",
+		Attributes: map[string]interface{}{
+			"Size":  "10",
+			"Price": 59.99,
+		},
+	}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "added", results[0].Status)
+
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,130 @@
+package marketo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaximumAssetBatchSize is the largest number of records returnable in a
+	// single call to Marketo's asset API.
+	MaximumAssetBatchSize = 200
+)
+
+// AssetQuery contains the possible parameters used when listing Marketo
+// assets. Unlike the REST API's Query, asset APIs page using an offset and
+// maximum number of records rather than a paging token. Asset list methods
+// request pages of MaxReturn records, starting at Offset, until a page with
+// fewer records is returned.
+type AssetQuery struct {
+	Offset       int
+	MaxReturn    int
+	FilterType   string
+	FilterValues []string
+	// Root and MaxDepth are used when browsing folders
+	Root     *FolderReference
+	MaxDepth int
+}
+
+// Values returns the query parameters for the asset query
+func (q *AssetQuery) Values() url.Values {
+	values := url.Values{}
+	if q.Offset > 0 {
+		values.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.MaxReturn > 0 {
+		values.Set("maxReturn", strconv.Itoa(q.MaxReturn))
+	}
+	if q.FilterType != "" {
+		values.Set("filterType", q.FilterType)
+		values.Set("filterValues", strings.Join(q.FilterValues, ","))
+	}
+	if q.Root != nil {
+		root, _ := json.Marshal(q.Root)
+		values.Set("root", string(root))
+	}
+	if q.MaxDepth > 0 {
+		values.Set("maxDepth", strconv.Itoa(q.MaxDepth))
+	}
+	return values
+}
+
+// AssetQueryOption defines the signature of functional options for Marketo
+// asset APIs.
+type AssetQueryOption func(*AssetQuery)
+
+// WithOffset sets the index of the first record to return
+func WithOffset(offset int) AssetQueryOption {
+	return func(q *AssetQuery) {
+		q.Offset = offset
+	}
+}
+
+// WithMaxReturn sets the maximum number of records to request per call;
+// the default is MaximumAssetBatchSize
+func WithMaxReturn(max int) AssetQueryOption {
+	return func(q *AssetQuery) {
+		q.MaxReturn = max
+	}
+}
+
+// AssetFilter sets the field and values to filter assets by
+func AssetFilter(filterType string, values ...string) AssetQueryOption {
+	return func(q *AssetQuery) {
+		q.FilterType = filterType
+		q.FilterValues = values
+	}
+}
+
+// WithRoot sets the folder to browse from
+func WithRoot(root FolderReference) AssetQueryOption {
+	return func(q *AssetQuery) {
+		q.Root = &root
+	}
+}
+
+// WithMaxDepth sets the number of folder levels to traverse when browsing
+func WithMaxDepth(depth int) AssetQueryOption {
+	return func(q *AssetQuery) {
+		q.MaxDepth = depth
+	}
+}
+
+// pageAssets requests pages of assets from endpoint until a page with fewer
+// than q.MaxReturn records is returned. decode is called with each response
+// and returns the number of records it contained.
+func (c *Client) pageAssets(ctx context.Context, operation, endpoint string, q AssetQuery, decode func(json.RawMessage) (int, error)) error {
+	if q.MaxReturn == 0 {
+		q.MaxReturn = MaximumAssetBatchSize
+	}
+
+	for {
+		request, err := http.NewRequestWithContext(ctx,
+			http.MethodGet, endpoint+"?"+q.Values().Encode(), nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		response, err := c.doResult(request, operation, nil)
+		if err != nil {
+			return err
+		}
+		// Marketo omits the result entirely when no assets are found
+		if len(response.Result) == 0 {
+			return nil
+		}
+		count, err := decode(response.Result)
+		if err != nil {
+			return err
+		}
+		if count < q.MaxReturn {
+			return nil
+		}
+		q.Offset += count
+	}
+}
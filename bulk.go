@@ -1,23 +1,28 @@
 package marketo
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"encoding/csv"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 type ImportObject struct {
 	create   string
 	status   string
 	failures string
+	warnings string
 }
 
 var (
@@ -25,6 +30,7 @@ var (
 		create:   "leads",
 		status:   "leads/batch/%d",
 		failures: "leads/batch/%d/failures",
+		warnings: "leads/batch/%d/warnings",
 	}
 	importObjects = map[string]ImportObject{
 		"lead": Leads,
@@ -32,46 +38,137 @@ var (
 )
 
 // ImportObjectForAPIName returns the ImportObject given the API name
-// of a Marketo object
+// of a Marketo object.
+//
+// Files imported for a custom object must use the object's field API names
+// as the CSV header, and must include every one of its dedupe fields (see
+// CustomObjectMetadata.DedupeFields) so Marketo can match rows to existing
+// records; unlike leads, there is no lookup field to choose. Link fields
+// relating the object to its parent must also be present.
 func ImportObjectForAPIName(apiName string) ImportObject {
 	if obj, ok := importObjects[apiName]; ok {
 		return obj
 	}
 
+	name := escapeName(apiName)
+	// the batch paths are format strings, so escapes in them are doubled
+	format := strings.ReplaceAll(name, "%", "%%")
 	return ImportObject{
-		create:   fmt.Sprintf("customobjects/%s/import", apiName),
-		status:   fmt.Sprintf("customobjects/%s/import/%%d/status", apiName),
-		failures: fmt.Sprintf("customobjects/%s/import/%%d/failures", apiName),
+		create:   "customobjects/" + name + "/import",
+		status:   "customobjects/" + format + "/import/%d/status",
+		failures: "customobjects/" + format + "/import/%d/failures",
+		warnings: "customobjects/" + format + "/import/%d/warnings",
 	}
 }
 
+// BatchStatus is the processing status of an import batch
+type BatchStatus string
+
 const (
-	BatchComplete  = "Complete"
-	BatchQueued    = "Queued"
-	BatchImporting = "Importing"
-	BatchFailed    = "Failed"
+	BatchComplete  BatchStatus = "Complete"
+	BatchQueued    BatchStatus = "Queued"
+	BatchImporting BatchStatus = "Importing"
+	BatchFailed    BatchStatus = "Failed"
 )
 
 const (
 	createImport      = "create bulk import"
 	getImport         = "get import status"
 	getImportFailures = "get import failures"
+	getImportWarnings = "get import warnings"
 )
 
 // BatchResult contains the details of a batch, returned by the Create
 // & Get functions
 type BatchResult struct {
-	BatchID          int    `json:"batchId"`
-	ImportID         string `json:"importId"`
-	Status           string `json:"status"`
-	LeadsProcessed   int    `json:"numOfLeadsProcessed,omitempty"`
-	Failures         int    `json:"numOfRowsFailed"`
-	Warnings         int    `json:"numOfRowsWithWarning"`
-	Message          string `json:"message"`
-	ObjectsProcessed int    `json:"numOfObjectsProcessed,omitempty"`
-	ObjectName       string `json:"objectApiName,omitempty"`
+	BatchID          int         `json:"batchId"`
+	ImportID         string      `json:"importId"`
+	Status           BatchStatus `json:"status"`
+	LeadsProcessed   int         `json:"numOfLeadsProcessed,omitempty"`
+	Failures         int         `json:"numOfRowsFailed"`
+	Warnings         int         `json:"numOfRowsWithWarning"`
+	Message          string      `json:"message"`
+	ObjectsProcessed int         `json:"numOfObjectsProcessed,omitempty"`
+	ObjectName       string      `json:"objectApiName,omitempty"`
 
 	Processed int `json:"-"`
+	// Messages contains each message returned for the batch; Message
+	// contains them joined into a single string.
+	Messages []string `json:"-"`
+	// Raw contains the batch as returned by Marketo, including any fields
+	// not decoded above.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a batch result, accepting the message as either a
+// single string or a list of strings.
+func (r *BatchResult) UnmarshalJSON(data []byte) error {
+	type batchResult BatchResult
+	aux := struct {
+		*batchResult
+		Message json.RawMessage `json:"message"`
+	}{
+		batchResult: (*batchResult)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Raw = append(json.RawMessage(nil), data...)
+
+	// leads and custom objects report the processed count in different
+	// fields
+	r.Processed = r.ObjectsProcessed
+	if r.LeadsProcessed > 0 {
+		r.Processed = r.LeadsProcessed
+	}
+
+	r.Message = ""
+	r.Messages = nil
+	if len(aux.Message) == 0 || string(aux.Message) == "null" {
+		return nil
+	}
+
+	var message string
+	if err := json.Unmarshal(aux.Message, &message); err == nil {
+		r.Message = message
+		r.Messages = []string{message}
+		return nil
+	}
+	if err := json.Unmarshal(aux.Message, &r.Messages); err != nil {
+		return err
+	}
+	r.Message = strings.Join(r.Messages, "; ")
+	return nil
+}
+
+// IsTerminal returns true if the batch has finished processing, either
+// successfully or not.
+func (r BatchResult) IsTerminal() bool {
+	return r.IsComplete() || r.IsFailed()
+}
+
+// IsComplete returns true if the batch completed processing
+func (r BatchResult) IsComplete() bool {
+	return r.Status == BatchComplete
+}
+
+// PercentComplete returns the percentage, from 0 to 100, of totalRows which
+// have been processed, either successfully or not. Marketo does not report
+// the number of rows in a batch, so the caller must provide it.
+func (r BatchResult) PercentComplete(totalRows int) float64 {
+	if totalRows <= 0 {
+		return 0
+	}
+	percent := float64(r.Processed+r.Failures) / float64(totalRows) * 100
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// IsFailed returns true if the batch failed
+func (r BatchResult) IsFailed() bool {
+	return r.Status == BatchFailed
 }
 
 // ImportAPI provides access to the Marketo import API
@@ -85,9 +182,132 @@ func NewImportAPI(c *Client) *ImportAPI {
 	return &ImportAPI{c}
 }
 
+// importOptions contains the optional parameters for creating an import
+type importOptions struct {
+	params        url.Values
+	dedupeColumns []string
+	progress      func(bytesSent int64)
+	columns       []string
+	dropUnknown   bool
+	nullValue     string
+	maxColumns    int
+
+	idempotencyKey string
+	store          ImportStore
+}
+
+// ImportOption defines the signature of functional options for Marketo
+// bulk import APIs.
+//
+// There is no option for a lenient or strict import mode: Marketo's bulk
+// import endpoints accept only format, lookupField, listId and
+// partitionName, and have no mode parameter. Rows with invalid fields are
+// always reported as failures, which can be read using Failures.
+type ImportOption func(*importOptions)
+
+// WithLookupField sets the field Marketo uses to match imported records to
+// existing ones, for example "email" or "id" for leads. When omitted,
+// Marketo uses the instance's default dedupe field.
+func WithLookupField(field string) ImportOption {
+	return func(o *importOptions) {
+		o.params.Set("lookupField", field)
+	}
+}
+
+// WithDedupeColumns requires the CSV header to contain the provided columns,
+// returning an error from Create before uploading if any are missing. Custom
+// objects are matched to existing records using their dedupe fields, such
+// as an external ID, so omitting one creates duplicate records on re-import;
+// pass the object's CustomObjectMetadata.DedupeFields to guard against this.
+func WithDedupeColumns(columns ...string) ImportOption {
+	return func(o *importOptions) {
+		o.dedupeColumns = append(o.dedupeColumns, columns...)
+	}
+}
+
+// WithUploadProgress calls fn as the import file is sent to Marketo, with
+// the total number of bytes of the request body sent so far. The body
+// includes multipart framing, so the total is slightly larger than the
+// file. If the upload is retried, counting starts again from zero.
+func WithUploadProgress(fn func(bytesSent int64)) ImportOption {
+	return func(o *importOptions) {
+		o.progress = fn
+	}
+}
+
+// WithColumns sets the columns, in order, of the file generated by
+// CreateRecords. Keys missing from a record are written as empty cells;
+// records with keys not listed cause an error unless WithDropUnknownColumns
+// is also set.
+func WithColumns(columns ...string) ImportOption {
+	return func(o *importOptions) {
+		o.columns = columns
+	}
+}
+
+// WithDropUnknownColumns causes CreateRecords to omit record keys which are
+// not listed by WithColumns, rather than returning an error.
+func WithDropUnknownColumns() ImportOption {
+	return func(o *importOptions) {
+		o.dropUnknown = true
+	}
+}
+
+// WithNullValue sets the value CreateRecords writes for Null, which clears
+// the field; DefaultNullValue is used when omitted.
+func WithNullValue(value string) ImportOption {
+	return func(o *importOptions) {
+		o.nullValue = value
+	}
+}
+
+// WithMaxColumns sets the largest number of columns CreateRecords will
+// generate, such as the number of fields of the object being imported.
+// Column limit detection is off unless this option is set with n > 0: by
+// default CreateRecords uploads files of any width and Marketo rejects
+// those with too many columns.
+func WithMaxColumns(n int) ImportOption {
+	return func(o *importOptions) {
+		o.maxColumns = n
+	}
+}
+
+// progressReader calls fn with the cumulative number of bytes read after
+// each read
+type progressReader struct {
+	io.Reader
+	sent int64
+	fn   func(int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.fn(r.sent)
+	}
+	return n, err
+}
+
 // Create uploads a new file for importing, returning the new
 // asynchronous import
-func (i *ImportAPI) Create(ctx context.Context, obj ImportObject, file io.Reader) ([]BatchResult, error) {
+func (i *ImportAPI) Create(ctx context.Context, obj ImportObject, file io.Reader, opts ...ImportOption) (_ []BatchResult, err error) {
+	o := &importOptions{
+		params: url.Values{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.params.Set("format", "csv")
+
+	if len(o.dedupeColumns) > 0 {
+		var err error
+		file, err = requireColumns(file, o.dedupeColumns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	buffer := &strings.Builder{}
 	mpWriter := multipart.NewWriter(buffer)
 	h := make(textproto.MIMEHeader)
@@ -98,22 +318,55 @@ func (i *ImportAPI) Create(ctx context.Context, obj ImportObject, file io.Reader
 	if err != nil {
 		return nil, err
 	}
-	_, err = io.Copy(fileWriter, file)
+	hash := importHash(obj, o)
+	_, err = io.Copy(fileWriter, io.TeeReader(file, hash))
 	if err != nil {
 		return nil, err
 	}
 
+	storeKey := ""
+	if o.store != nil {
+		storeKey = importStoreKey(o.idempotencyKey, hash)
+		var (
+			batches []BatchResult
+			claimed bool
+		)
+		batches, claimed, err = o.store.Claim(withClock(ctx, i.Client.clock), storeKey)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			return batches, nil
+		}
+		defer func() {
+			if err != nil {
+				// allow the import to be retried
+				o.store.Release(withClock(ctx, i.Client.clock), storeKey)
+			}
+		}()
+	}
+
 	mpWriter.Close()
-	request, err := http.NewRequest(http.MethodPost,
-		i.url("bulk", "v1", fmt.Sprintf("%s.json?format=csv", obj.create)),
-		bytes.NewBufferString(buffer.String()),
+	body := buffer.String()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		i.url("bulk", "v1", fmt.Sprintf("%s.json?%s", obj.create, o.params.Encode())),
+		strings.NewReader(body),
 	)
 	if err != nil {
 		return nil, err
 	}
 	request.Header.Add("Content-Type", mpWriter.FormDataContentType())
+	if o.progress != nil {
+		request.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(&progressReader{
+				Reader: strings.NewReader(body),
+				fn:     o.progress,
+			}), nil
+		}
+		request.Body, _ = request.GetBody()
+	}
 
-	resp, err := i.Client.doRequest(request)
+	resp, err := i.Client.doRequest(request, createImport)
 	if err != nil {
 		return nil, err
 	}
@@ -128,8 +381,8 @@ func (i *ImportAPI) Create(ctx context.Context, obj ImportObject, file io.Reader
 	if err != nil {
 		return nil, err
 	}
-	if len(response.Errors) > 0 {
-		return nil, ErrorForReasons(resp.StatusCode, response.Errors...)
+	if err = responseError(resp, response); err != nil {
+		return nil, err
 	}
 
 	results := []BatchResult{}
@@ -138,12 +391,43 @@ func (i *ImportAPI) Create(ctx context.Context, obj ImportObject, file io.Reader
 		return nil, err
 	}
 
+	if o.store != nil {
+		if err := o.store.Put(withClock(ctx, i.Client.clock), storeKey, results); err != nil {
+			return results, err
+		}
+	}
 	return results, nil
 }
 
+// requireColumns reads the CSV header from file, returning an error if any
+// of columns are missing, along with a reader that replays the entire file.
+// The header is checked as Validate checks it.
+func requireColumns(file io.Reader, columns []string) (io.Reader, error) {
+	buffered := bufio.NewReader(file)
+	line, err := buffered.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	header, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read import header: %w", err)
+	}
+
+	problems := importSchema{dedupe: columns}.checkHeader(header)
+	if len(problems) > 0 {
+		absent := make([]string, len(problems))
+		for i, problem := range problems {
+			absent[i] = problem.Column
+		}
+		return nil, fmt.Errorf("import is missing dedupe columns: %s",
+			strings.Join(absent, ", "))
+	}
+	return io.MultiReader(strings.NewReader(line), buffered), nil
+}
+
 // Get retrieves an existing import by its batch ID
 func (i *ImportAPI) Get(ctx context.Context, obj ImportObject, id int) (*BatchResult, error) {
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(ctx,
 		http.MethodGet, i.url("bulk", "v1", fmt.Sprintf("%s.json",
 			fmt.Sprintf(obj.status, id),
 		)), nil,
@@ -152,7 +436,7 @@ func (i *ImportAPI) Get(ctx context.Context, obj ImportObject, id int) (*BatchRe
 		return nil, err
 	}
 
-	resp, err := i.Client.doRequest(request)
+	resp, err := i.Client.doRequest(request, getImport)
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +451,8 @@ func (i *ImportAPI) Get(ctx context.Context, obj ImportObject, id int) (*BatchRe
 	if err != nil {
 		return nil, err
 	}
-	if len(response.Errors) > 0 {
-		return nil, ErrorForReasons(resp.StatusCode, response.Errors...)
+	if err = responseError(resp, response); err != nil {
+		return nil, err
 	}
 
 	result := []BatchResult{}
@@ -177,37 +461,217 @@ func (i *ImportAPI) Get(ctx context.Context, obj ImportObject, id int) (*BatchRe
 		return nil, err
 	}
 	if len(result) < 1 {
-		return nil, errors.New("not found")
+		return nil, notFoundError{what: fmt.Sprintf("import batch %d", id)}
 	}
+	return &result[0], nil
+}
 
-	for i, r := range result {
-		result[i].Processed = r.ObjectsProcessed
-		if r.LeadsProcessed > 0 {
-			result[i].Processed = r.LeadsProcessed
+// WaitForComplete polls the status of an import every interval until it
+// completes or fails, returning the final status. Polling stops early if ctx
+// is cancelled.
+func (i *ImportAPI) WaitForComplete(ctx context.Context, obj ImportObject, id int, interval time.Duration) (*BatchResult, error) {
+	for {
+		result, err := i.Get(ctx, obj, id)
+		if err != nil {
+			return nil, err
+		}
+		if result.IsTerminal() {
+			return result, nil
+		}
+
+		if err := sleep(ctx, i.Client.clock, interval); err != nil {
+			return nil, err
 		}
 	}
-	return &result[0], nil
+}
+
+// ImportRowsError is returned by WaitForClean when an import completes with
+// failed rows or rows with warnings.
+type ImportRowsError struct {
+	BatchID  int
+	Failures []LeadImportFailure
+	Warnings []LeadImportFailure
+}
+
+// Error fulfills the error interface
+func (e *ImportRowsError) Error() string {
+	return fmt.Sprintf("batch %d: %d rows failed, %d rows with warnings",
+		e.BatchID, len(e.Failures), len(e.Warnings))
+}
+
+// WaitForClean waits for an import to complete, as WaitForComplete does, and
+// returns an *ImportRowsError containing the affected rows if any failed or
+// were imported with warnings. The final status is returned along with the
+// error.
+func (i *ImportAPI) WaitForClean(ctx context.Context, obj ImportObject, id int, interval time.Duration) (*BatchResult, error) {
+	result, err := i.WaitForComplete(ctx, obj, id, interval)
+	if err != nil {
+		return nil, err
+	}
+	if result.Failures == 0 && result.Warnings == 0 {
+		return result, nil
+	}
+
+	rowsErr := &ImportRowsError{BatchID: id}
+	if result.Failures > 0 {
+		rowsErr.Failures, err = i.Failures(ctx, obj, id)
+		if err != nil {
+			return result, err
+		}
+	}
+	if result.Warnings > 0 {
+		rowsErr.Warnings, err = i.Warnings(ctx, obj, id)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, rowsErr
+}
+
+// BatchErrors contains the errors encountered waiting for multiple batches,
+// keyed by batch ID.
+type BatchErrors map[int]error
+
+// Error fulfills the error interface
+func (e BatchErrors) Error() string {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("batch %d: %s", id, e[id])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// WaitForAll concurrently polls the status of each import until all have
+// completed or failed, returning the final status of each in the order the
+// IDs were provided. If polling any batch fails, the results for the
+// remaining batches are returned along with a BatchErrors.
+func (i *ImportAPI) WaitForAll(ctx context.Context, obj ImportObject, ids []int, interval time.Duration) ([]BatchResult, error) {
+	results := make([]BatchResult, len(ids))
+	errs := BatchErrors{}
+
+	wg := sync.WaitGroup{}
+	lock := sync.Mutex{}
+	for idx, id := range ids {
+		wg.Add(1)
+		go func(idx, id int) {
+			defer wg.Done()
+			result, err := i.WaitForComplete(ctx, obj, id, interval)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs[id] = err
+				return
+			}
+			results[idx] = *result
+		}(idx, id)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
 }
 
 // LeadImportFailure contains a single lead record failure, along with
-// the reason for failure.
+// the reason for failure and the batch the record was imported in.
 type LeadImportFailure struct {
-	Reason string
-	Fields map[string]interface{}
+	BatchID int
+	Reason  string
+	Fields  map[string]interface{}
 }
 
 // Failures returns the list of failed recrods for an import
 func (i *ImportAPI) Failures(ctx context.Context, obj ImportObject, id int) ([]LeadImportFailure, error) {
-	request, err := http.NewRequest(
-		http.MethodGet, i.url("bulk", "v1", fmt.Sprintf("%s.json",
-			fmt.Sprintf(obj.failures, id),
-		)), nil,
+	return i.rows(ctx, getImportFailures, fmt.Sprintf(obj.failures, id), id)
+}
+
+// AllFailures returns the failed records of each of the provided batches,
+// such as those returned by Create for a file imported in several batches,
+// as a single list. The BatchID of each failure identifies the batch it
+// came from, so the records can be reprocessed.
+func (i *ImportAPI) AllFailures(ctx context.Context, obj ImportObject, ids []int) ([]LeadImportFailure, error) {
+	result := []LeadImportFailure{}
+	for _, id := range ids {
+		failures, err := i.Failures(ctx, obj, id)
+		if err != nil {
+			return nil, fmt.Errorf("batch %d: %w", id, err)
+		}
+		result = append(result, failures...)
+	}
+	return result, nil
+}
+
+// Warnings returns the list of records imported with warnings; the Reason
+// of each contains the warning.
+func (i *ImportAPI) Warnings(ctx context.Context, obj ImportObject, id int) ([]LeadImportFailure, error) {
+	return i.rows(ctx, getImportWarnings, fmt.Sprintf(obj.warnings, id), id)
+}
+
+// FailureTracker fetches the failures for a batch incrementally, returning
+// only the failures which have not been returned by a previous poll. Marketo
+// makes failures available while a batch is still importing, and returns all
+// failures so far on each request.
+type FailureTracker struct {
+	api  *ImportAPI
+	obj  ImportObject
+	id   int
+	seen map[string]int
+}
+
+// TrackFailures returns a FailureTracker for the provided batch
+func (i *ImportAPI) TrackFailures(obj ImportObject, id int) *FailureTracker {
+	return &FailureTracker{
+		api:  i,
+		obj:  obj,
+		id:   id,
+		seen: map[string]int{},
+	}
+}
+
+// Poll returns the failures for the batch which were not returned by a
+// previous call. Rows are identified by their contents, so identical rows
+// are each returned once.
+func (t *FailureTracker) Poll(ctx context.Context) ([]LeadImportFailure, error) {
+	failures, err := t.api.Failures(ctx, t.obj, t.id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	result := []LeadImportFailure{}
+	for _, failure := range failures {
+		key, err := json.Marshal(failure)
+		if err != nil {
+			return nil, err
+		}
+		counts[string(key)]++
+		if counts[string(key)] > t.seen[string(key)] {
+			t.seen[string(key)] = counts[string(key)]
+			result = append(result, failure)
+		}
+	}
+	return result, nil
+}
+
+// rows returns the records in the import failure or warning file of batch
+// id, whose final column contains the reason for each.
+func (i *ImportAPI) rows(ctx context.Context, operation, path string, id int) ([]LeadImportFailure, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet, i.url("bulk", "v1", fmt.Sprintf("%s.json", path)), nil,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := i.Client.doRequest(request)
+	resp, err := i.Client.doRequest(request, operation)
 	if err != nil {
 		return nil, err
 	}
@@ -217,10 +681,13 @@ func (i *ImportAPI) Failures(ctx context.Context, obj ImportObject, id int) ([]L
 		return nil, nil
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, handleError(getImportFailures, resp)
+		return nil, handleError(operation, resp)
 	}
 
 	reader := csv.NewReader(resp.Body)
+	// row lengths are validated below so that a malformed row results in
+	// a descriptive error instead of a silently truncated result
+	reader.FieldsPerRecord = -1
 	header, err := reader.Read()
 	if err != nil {
 		return nil, err
@@ -229,9 +696,16 @@ func (i *ImportAPI) Failures(ctx context.Context, obj ImportObject, id int) ([]L
 	failures := []LeadImportFailure{}
 	record, err := reader.Read()
 	for err == nil {
+		if len(record) != len(header) {
+			return nil, fmt.Errorf(
+				"malformed failure record %d: expected %d columns, got %d",
+				len(failures)+1, len(header), len(record),
+			)
+		}
 		failure := LeadImportFailure{
-			Reason: record[len(header)-1],
-			Fields: map[string]interface{}{},
+			BatchID: id,
+			Reason:  record[len(header)-1],
+			Fields:  map[string]interface{}{},
 		}
 		for i := 0; i < len(header)-1; i++ {
 			failure.Fields[header[i]] = record[i]
@@ -239,5 +713,8 @@ func (i *ImportAPI) Failures(ctx context.Context, obj ImportObject, id int) ([]L
 		failures = append(failures, failure)
 		record, err = reader.Read()
 	}
+	if err != io.EOF {
+		return nil, err
+	}
 	return failures, nil
 }
@@ -0,0 +1,539 @@
+package marketo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestImportFailures(t *testing.T) {
+	t.Run("well formed", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/bulk/v1/leads/batch/1/failures.json").
+			Reply(http.StatusOK).
+			BodyString("email,firstName,Import Failure Reason\n" +
+				"nathan@polytomic,Nathan,Invalid email\n")
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewImportAPI(client)
+		failures, err := api.Failures(context.Background(), Leads, 1)
+		require.NoError(t, err)
+		require.Len(t, failures, 1)
+		assert.Equal(t, "Invalid email", failures[0].Reason)
+		assert.Equal(t, "Nathan", failures[0].Fields["firstName"])
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("ragged rows", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/bulk/v1/leads/batch/1/failures.json").
+			Reply(http.StatusOK).
+			BodyString("email,firstName,Import Failure Reason\n" +
+				"nathan@polytomic,Nathan,Invalid email\n" +
+				"ghalib@polytomic\n")
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewImportAPI(client)
+		failures, err := api.Failures(context.Background(), Leads, 1)
+		assert.Error(t, err)
+		assert.Nil(t, failures)
+
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestImportAllFailures(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1/failures.json").
+		Reply(http.StatusOK).
+		BodyString("email,Import Failure Reason\n" +
+			"nathan@polytomic,Invalid email\n")
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/2/failures.json").
+		Reply(http.StatusNotFound)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/3/failures.json").
+		Reply(http.StatusOK).
+		BodyString("email,Import Failure Reason\n" +
+			"ghalib@polytomic,Invalid email\n")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+	})
+	require.NoError(t, err)
+
+	failures, err := NewImportAPI(client).AllFailures(context.Background(), Leads, []int{1, 2, 3})
+	require.NoError(t, err)
+	require.Len(t, failures, 2)
+	assert.Equal(t, 1, failures[0].BatchID)
+	assert.Equal(t, "nathan@polytomic", failures[0].Fields["email"])
+	assert.Equal(t, 3, failures[1].BatchID)
+	assert.Equal(t, "ghalib@polytomic", failures[1].Fields["email"])
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestImportCreate(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		MatchParam("format", "csv").
+		MatchParam("lookupField", "email").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			_, err := ioutil.ReadAll(r.Body)
+			return err == nil, err
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"requestId":"e42b#14272d07d78","success":true,"result":[{"batchId":1,"status":"Importing"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	var progress []int64
+	api := NewImportAPI(client)
+	batches, err := api.Create(
+		context.Background(),
+		Leads,
+		strings.NewReader("email\nnathan@polytomic.com\n"),
+		WithLookupField("email"),
+		WithUploadProgress(func(sent int64) {
+			progress = append(progress, sent)
+		}),
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, progress)
+	assert.Greater(t, progress[len(progress)-1], int64(len("email\nnathan@polytomic.com\n")))
+	require.Len(t, batches, 1)
+	assert.Equal(t, 1, batches[0].BatchID)
+	assert.Equal(t, BatchImporting, batches[0].Status)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestImportGetNotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	_, err = api.Get(context.Background(), Leads, 1)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, IsNotFound(err))
+	assert.EqualError(t, err, "import batch 1 not found")
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestImportWaitForAll(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":1,"status":"Importing"}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":1,"status":"Complete","numOfLeadsProcessed":10}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/2.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":2,"status":"Failed"}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/3.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	results, err := api.WaitForAll(context.Background(), Leads, []int{1, 2, 3}, time.Millisecond)
+	require.Error(t, err)
+
+	errs, ok := err.(BatchErrors)
+	require.True(t, ok)
+	assert.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[3], ErrNotFound))
+
+	require.Len(t, results, 3)
+	assert.Equal(t, BatchComplete, results[0].Status)
+	assert.Equal(t, 10, results[0].Processed)
+	assert.Equal(t, BatchFailed, results[1].Status)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestBatchResultStatus(t *testing.T) {
+	assert.False(t, BatchResult{Status: BatchQueued}.IsTerminal())
+	assert.False(t, BatchResult{Status: BatchImporting}.IsTerminal())
+
+	assert.True(t, BatchResult{Status: BatchComplete}.IsTerminal())
+	assert.True(t, BatchResult{Status: BatchComplete}.IsComplete())
+	assert.False(t, BatchResult{Status: BatchComplete}.IsFailed())
+
+	assert.True(t, BatchResult{Status: BatchFailed}.IsTerminal())
+	assert.True(t, BatchResult{Status: BatchFailed}.IsFailed())
+	assert.False(t, BatchResult{Status: BatchFailed}.IsComplete())
+}
+
+func TestBatchResultMessages(t *testing.T) {
+	t.Run("single message", func(t *testing.T) {
+		result := BatchResult{}
+		require.NoError(t, json.Unmarshal(
+			[]byte(`{"batchId":1,"status":"Failed","message":"Import failed: column 'emial' not found"}`),
+			&result,
+		))
+		assert.Equal(t, 1, result.BatchID)
+		assert.Equal(t, BatchFailed, result.Status)
+		assert.Equal(t, "Import failed: column 'emial' not found", result.Message)
+		assert.Equal(t, []string{"Import failed: column 'emial' not found"}, result.Messages)
+	})
+
+	t.Run("multiple messages", func(t *testing.T) {
+		result := BatchResult{}
+		require.NoError(t, json.Unmarshal(
+			[]byte(`{"batchId":1,"status":"Failed","message":["Column 'emial' not found","Column 'compnay' not found"]}`),
+			&result,
+		))
+		assert.Equal(t, []string{"Column 'emial' not found", "Column 'compnay' not found"}, result.Messages)
+		assert.Equal(t, "Column 'emial' not found; Column 'compnay' not found", result.Message)
+	})
+}
+
+func TestBatchResultRaw(t *testing.T) {
+	data := `{"batchId":1,"status":"Complete","numOfRowsSkipped":3}`
+	result := BatchResult{}
+	require.NoError(t, json.Unmarshal([]byte(data), &result))
+
+	extra := struct {
+		Skipped int `json:"numOfRowsSkipped"`
+	}{}
+	require.NoError(t, json.Unmarshal(result.Raw, &extra))
+	assert.Equal(t, 3, extra.Skipped)
+}
+
+func TestImportGetUnsuccessful(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1.json").
+		Reply(http.StatusOK).
+		JSON(`{"requestId":"e42b#14272d07d78","success":false,"errors":[]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	_, err = api.Get(context.Background(), Leads, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "e42b#14272d07d78")
+
+	var mErr Error
+	require.True(t, errors.As(err, &mErr))
+	assert.Equal(t, "e42b#14272d07d78", mErr.RequestID)
+	assert.Equal(t, 1, mErr.Attempts)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestBatchResultPercentComplete(t *testing.T) {
+	result := BatchResult{Processed: 40, Failures: 10}
+	assert.Equal(t, 50.0, result.PercentComplete(100))
+	assert.Equal(t, 100.0, result.PercentComplete(20))
+	assert.Equal(t, 0.0, result.PercentComplete(0))
+}
+
+func TestImportObjectForAPIName(t *testing.T) {
+	obj := ImportObjectForAPIName("test object%c")
+	assert.Equal(t, "customobjects/test%20object%25c/import", obj.create)
+	assert.Equal(t, "customobjects/test%20object%25c/import/7/status", fmt.Sprintf(obj.status, 7))
+	assert.Equal(t, "customobjects/test%20object%25c/import/7/failures", fmt.Sprintf(obj.failures, 7))
+}
+
+func TestImportCustomObject(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/customobjects/testObject_c/import.json").
+		MatchParam("format", "csv").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":1022,"operation":"import","status":"Queued","objectApiName":"testObject_c"}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/customobjects/testObject_c/import/1022/status.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{
+			"batchId": 1022,
+			"operation": "import",
+			"status": "Complete",
+			"objectApiName": "testObject_c",
+			"numOfObjectsProcessed": 2,
+			"numOfRowsFailed": 1,
+			"numOfRowsWithWarning": 0,
+			"message": "Import completed with errors, 2 records imported (2 members), 1 failed"
+		}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/customobjects/testObject_c/import/1022/failures.json").
+		Reply(http.StatusOK).
+		BodyString("email,firstName,Import Failure Reason\n" +
+			"nathan@polytomic,Nathan,Invalid email\n")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	obj := ImportObjectForAPIName("testObject_c")
+
+	batches, err := api.Create(context.Background(), obj, strings.NewReader(
+		"email,firstName\n"+
+			"nathan@polytomic.com,Nathan\n"+
+			"ghalib@polytomic.com,Ghalib\n"+
+			"nathan@polytomic,Nathan\n",
+	))
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, "testObject_c", batches[0].ObjectName)
+
+	batch, err := api.Get(context.Background(), obj, batches[0].BatchID)
+	require.NoError(t, err)
+	assert.True(t, batch.IsComplete())
+	assert.Equal(t, "testObject_c", batch.ObjectName)
+	assert.Equal(t, 2, batch.ObjectsProcessed)
+	assert.Equal(t, 2, batch.Processed)
+	assert.Equal(t, 1, batch.Failures)
+
+	failures, err := api.Failures(context.Background(), obj, batch.BatchID)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "Invalid email", failures[0].Reason)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestWaitForClean(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":1,"status":"Complete","numOfLeadsProcessed":2,"numOfRowsFailed":0,"numOfRowsWithWarning":1}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1/warnings.json").
+		Reply(http.StatusOK).
+		BodyString("email,firstName,Import Warning Reason\n" +
+			"nathan@polytomic.com,Nathan,Field value truncated\n")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	result, err := api.WaitForClean(context.Background(), Leads, 1, time.Millisecond)
+	require.Error(t, err)
+	assert.Equal(t, BatchComplete, result.Status)
+
+	var rowsErr *ImportRowsError
+	require.True(t, errors.As(err, &rowsErr))
+	assert.Empty(t, rowsErr.Failures)
+	require.Len(t, rowsErr.Warnings, 1)
+	assert.Equal(t, "Field value truncated", rowsErr.Warnings[0].Reason)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestImportDedupeColumns(t *testing.T) {
+	obj := ImportObjectForAPIName("testObject_c")
+
+	t.Run("present", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Post("/bulk/v1/customobjects/testObject_c/import.json").
+			AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+				file, _, err := r.FormFile("file")
+				require.NoError(t, err)
+				body, err := ioutil.ReadAll(file)
+				require.NoError(t, err)
+				assert.Equal(t, "externalId,email\n1,nathan@polytomic.com\n", string(body))
+				return true, nil
+			}).
+			Reply(http.StatusOK).
+			JSON(`{"success":true,"result":[{"batchId":1,"status":"Queued"}]}`)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewImportAPI(client)
+		_, err = api.Create(context.Background(), obj,
+			strings.NewReader("externalId,email\n1,nathan@polytomic.com\n"),
+			WithDedupeColumns("externalId"),
+		)
+		require.NoError(t, err)
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		api := NewImportAPI(&Client{})
+		_, err := api.Create(context.Background(), obj,
+			strings.NewReader("email\nnathan@polytomic.com\n"),
+			WithDedupeColumns("externalId"),
+		)
+		assert.EqualError(t, err, "import is missing dedupe columns: externalId")
+	})
+}
+
+func TestFailureTracker(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1/failures.json").
+		Reply(http.StatusOK).
+		BodyString("email,Import Failure Reason\n" +
+			"nathan@polytomic,Invalid email\n")
+	gock.New(testHost).
+		Get("/bulk/v1/leads/batch/1/failures.json").
+		Reply(http.StatusOK).
+		BodyString("email,Import Failure Reason\n" +
+			"nathan@polytomic,Invalid email\n" +
+			"ghalib@polytomic,Invalid email\n")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	tracker := NewImportAPI(client).TrackFailures(Leads, 1)
+	failures, err := tracker.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "nathan@polytomic", failures[0].Fields["email"])
+
+	failures, err = tracker.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "ghalib@polytomic", failures[0].Fields["email"])
+
+	assert.True(t, gock.IsDone())
+}
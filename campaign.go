@@ -0,0 +1,161 @@
+package marketo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaximumCampaignLeads is the largest number of leads a smart campaign
+	// can be requested for in a single call.
+	MaximumCampaignLeads = 100
+)
+
+// Campaign is a Marketo smart campaign
+type Campaign struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Type          string `json:"type"`
+	ProgramID     int    `json:"programId"`
+	ProgramName   string `json:"programName"`
+	WorkspaceName string `json:"workspaceName"`
+	Active        bool   `json:"active"`
+	CreatedAt     string `json:"createdAt"`
+	UpdatedAt     string `json:"updatedAt"`
+}
+
+const (
+	listCampaigns   = "list campaigns"
+	getCampaign     = "get campaign"
+	requestCampaign = "request campaign"
+)
+
+// CampaignsAPI provides access to the Marketo smart campaigns API
+type CampaignsAPI struct {
+	c *Client
+}
+
+// NewCampaignsAPI returns a new instance of the campaigns API, configured
+// with the provided Client.
+func NewCampaignsAPI(c *Client) *CampaignsAPI {
+	return &CampaignsAPI{c: c}
+}
+
+// List returns a page of smart campaigns, along with the token for the next
+// page, if any. FilterField may be one of "id", "name", "programName", or
+// "workspaceName" to restrict the campaigns returned to those matching
+// FilterValues.
+func (a *CampaignsAPI) List(ctx context.Context, opts ...QueryOption) ([]Campaign, string, error) {
+	q := &Query{}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	query := url.Values{}
+	if q.FilterField != "" {
+		query.Set(q.FilterField, strings.Join(q.FilterValues, ","))
+	}
+	if q.BatchSize > 0 {
+		query.Set("batchSize", strconv.Itoa(q.BatchSize))
+	}
+	if q.NextPageToken != "" {
+		query.Set("nextPageToken", q.NextPageToken)
+	}
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		a.c.url("rest", "v1", "campaigns.json?"+query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	campaigns := []Campaign{}
+	response, err := a.c.doResult(request, listCampaigns, &campaigns)
+	if err != nil {
+		return nil, "", err
+	}
+	return campaigns, response.NextPageToken, nil
+}
+
+// Get returns the smart campaign with the provided ID
+func (a *CampaignsAPI) Get(ctx context.Context, id int) (*Campaign, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		a.c.url("rest", "v1", "campaigns", fmt.Sprintf("%d.json", id)),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	campaigns := []Campaign{}
+	_, err = a.c.doResult(request, getCampaign, &campaigns)
+	if err != nil {
+		return nil, err
+	}
+	if len(campaigns) == 0 {
+		return nil, ErrObjectNotFound
+	}
+	return &campaigns[0], nil
+}
+
+// Request triggers the smart campaign with the provided ID for a set of
+// leads. The campaign must have a "Campaign is Requested" trigger with the
+// Web Service API source. Tokens map my-token names, such as
+// "{{my.message}}", to the values used for this run.
+func (a *CampaignsAPI) Request(ctx context.Context, campaignID int, leadIDs []int, tokens map[string]string) error {
+	if len(leadIDs) == 0 {
+		return errors.New("too few leads")
+	}
+	if len(leadIDs) > MaximumCampaignLeads {
+		return errors.New("too many leads")
+	}
+
+	type leadID struct {
+		ID int `json:"id"`
+	}
+	type token struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	input := struct {
+		Leads  []leadID `json:"leads"`
+		Tokens []token  `json:"tokens,omitempty"`
+	}{}
+	for _, id := range leadIDs {
+		input.Leads = append(input.Leads, leadID{id})
+	}
+	for name, value := range tokens {
+		input.Tokens = append(input.Tokens, token{name, value})
+	}
+	sort.Slice(input.Tokens, func(i, j int) bool {
+		return input.Tokens[i].Name < input.Tokens[j].Name
+	})
+
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		a.c.url("rest", "v1", "campaigns", strconv.Itoa(campaignID), "trigger.json"),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Content-Type", "application/json")
+
+	_, err = a.c.doResult(request, requestCampaign, nil)
+	return err
+}
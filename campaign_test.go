@@ -0,0 +1,96 @@
+package marketo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestListCampaigns(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/campaigns.json").
+		MatchParam("programName", "Webinar Template").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"nextPageToken": "abc",
+			"result": [
+				{"id": 1004, "name": "Send Invite", "type": "trigger", "programId": 1107, "programName": "Webinar Template", "active": true}
+			]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCampaignsAPI(client)
+	campaigns, next, err := api.List(context.Background(),
+		FilterField("programName"),
+		FilterValues([]string{"Webinar Template"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", next)
+	require.Len(t, campaigns, 1)
+	assert.Equal(t, "Send Invite", campaigns[0].Name)
+	assert.True(t, campaigns[0].Active)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestRequestCampaign(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/campaigns/1004/trigger.json").
+		JSON(map[string]interface{}{
+			"input": map[string]interface{}{
+				"leads": []map[string]int{{"id": 1}, {"id": 2}},
+				"tokens": []map[string]string{
+					{"name": "{{my.date}}", "value": "June 1"},
+					{"name": "{{my.message}}", "value": "Hello"},
+				},
+			},
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":1004}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCampaignsAPI(client)
+	err = api.Request(context.Background(), 1004, []int{1, 2}, map[string]string{
+		"{{my.message}}": "Hello",
+		"{{my.date}}":    "June 1",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, gock.IsDone())
+
+	t.Run("too many leads", func(t *testing.T) {
+		err := api.Request(context.Background(), 1004, make([]int, MaximumCampaignLeads+1), nil)
+		assert.Error(t, err)
+	})
+}
@@ -2,9 +2,11 @@ package marketo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -18,6 +20,17 @@ const (
 	DefaultTimeout = 60
 	identityBase   = "/identity"
 	identityPath   = "/oauth/token"
+	// Version is the version of this client library
+	Version = "0.1.0"
+	// DefaultUserAgent is the User-Agent sent with API calls when none is
+	// configured
+	DefaultUserAgent = "go-marketo/" + Version
+
+	ping = "ping"
+
+	// maxDrainBytes is the most read from an unread response body before
+	// it is closed
+	maxDrainBytes = 64 << 10
 )
 
 // RecordResult holds Marketo record-level result
@@ -35,7 +48,7 @@ type Response struct {
 	MoreResult    bool            `json:"moreResult,omitempty"`
 	Errors        []Reason        `json:"errors,omitempty"`
 	Result        json.RawMessage `json:"result,omitempty"`
-	Warnings      []Reason        `json:"warning,omitempty"`
+	Warnings      []Reason        `json:"warnings,omitempty"`
 }
 
 // AuthToken holds data from Auth request
@@ -48,8 +61,11 @@ type AuthToken struct {
 
 // Client Marketo http Client
 type Client struct {
-	authClient       *http.Client
-	restClient       *http.Client
+	authClient *http.Client
+	restClient *http.Client
+	// streamClient sends requests for streamed files, which may take longer
+	// than the client's Timeout to read
+	streamClient     *http.Client
 	restRoundTripper *restRoundTripper
 	endpoint         string
 	identityEndpoint string
@@ -57,6 +73,20 @@ type Client struct {
 	auth             *AuthToken
 	tokenExpiresAt   time.Time
 	debug            bool
+	requestTimeout   time.Duration
+	// operationTimeouts overrides requestTimeout for individual operations
+	operationTimeouts map[Operation]time.Duration
+	// responseCache stores describe results when ConditionalRequests is
+	// enabled
+	responseCache *responseCache
+	// maxResponseBytes limits JSON response bodies when greater than zero
+	maxResponseBytes int64
+	retryPolicy      RetryPolicy
+	limiter          *rateLimiter
+	tokenSource      TokenSource
+	clock            Clock
+	statsLock        sync.Mutex
+	stats            ClientStats
 }
 
 // authRoundTripper wrapper for authentication query params
@@ -67,29 +97,63 @@ type authRoundTripper struct {
 }
 
 func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	if rt.delegate == nil {
-		rt.delegate = http.DefaultTransport
+	delegate := rt.delegate
+	if delegate == nil {
+		delegate = http.DefaultTransport
 	}
 	values := req.URL.Query()
 	values.Add("client_id", rt.clientID)
 	values.Add("client_secret", rt.clientSecret)
 	values.Add("grant_type", "client_credentials")
 	req.URL.RawQuery = values.Encode()
-	return rt.delegate.RoundTrip(req)
+	return delegate.RoundTrip(req)
 }
 
 // restRoundTripper wrapper for adding bearer token
 type restRoundTripper struct {
-	delegate http.RoundTripper
-	token    string
+	delegate  http.RoundTripper
+	userAgent string
+	headers   http.Header
+	tokenLock sync.RWMutex
+	token     string
+}
+
+func (rt *restRoundTripper) setToken(token string) {
+	rt.tokenLock.Lock()
+	defer rt.tokenLock.Unlock()
+	rt.token = token
 }
 
 func (rt *restRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	if rt.delegate == nil {
-		rt.delegate = http.DefaultTransport
+	delegate := rt.delegate
+	if delegate == nil {
+		delegate = http.DefaultTransport
+	}
+	// a RoundTripper must not modify the request, which is sent again
+	// when a call is retried
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", rt.userAgent)
+	}
+	addHeaders(req, rt.headers)
+	addHeaders(req, contextHeaders(req.Context()))
+	rt.tokenLock.RLock()
+	req.Header.Set("Authorization", "Bearer "+rt.token)
+	rt.tokenLock.RUnlock()
+	return delegate.RoundTrip(req)
+}
+
+// addHeaders adds headers to req, skipping any the request already has and
+// the Authorization header, which is set by the client
+func addHeaders(req *http.Request, headers http.Header) {
+	for key, values := range headers {
+		if http.CanonicalHeaderKey(key) == "Authorization" || req.Header.Get(key) != "" {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
 	}
-	req.Header.Add("Authorization", "Bearer "+rt.token)
-	return rt.delegate.RoundTrip(req)
 }
 
 // ClientConfig stores client configuration
@@ -98,9 +162,14 @@ type ClientConfig struct {
 	ID string
 	// Secret: Marketo client secret
 	Secret string
+	// TokenSource, optional: supplies access tokens managed outside the
+	// client, such as by a shared credential service, in place of
+	// authenticating with ID and Secret
+	TokenSource TokenSource
 	// Endpoint: https://xxx-xxx-xxx.mktorest.com
 	Endpoint string
-	// Timeout, optional: default http timeout is 60 seconds
+	// Timeout, optional: default http timeout is 60 seconds; it does not
+	// apply to streamed export files
 	Timeout uint
 	// Debug, optional: a flag to show logging output
 	Debug bool
@@ -110,9 +179,69 @@ type ClientConfig struct {
 	// RESTTransport, optional: the HTTP RoundTripper to use when
 	// making calls to the REST API.
 	RESTTransport http.RoundTripper
+	// DefaultRequestTimeout, optional: the timeout applied to API calls
+	// whose context does not already have a deadline, other than those
+	// streaming export files, which are limited only by their context and
+	// OperationTimeouts
+	DefaultRequestTimeout time.Duration
+	// OperationTimeouts, optional: timeouts which replace
+	// DefaultRequestTimeout for individual operations, such as
+	// OperationCreateImport. Timeout still limits each HTTP attempt, so it
+	// must be at least as long as the longest of these.
+	OperationTimeouts map[Operation]time.Duration
+	// ConditionalRequests, optional: cache describe results which include
+	// an ETag or Last-Modified header, sending If-None-Match or
+	// If-Modified-Since when describing the same object again and
+	// returning the cached result if Marketo responds 304 Not Modified
+	ConditionalRequests bool
+	// MaxResponseBytes, optional: the largest JSON response body read,
+	// guarding against a misbehaving endpoint or proxy; larger bodies
+	// result in ErrResponseTooLarge. Streamed files, such as exports and
+	// import failures, are not limited.
+	MaxResponseBytes int64
+	// RateLimit, optional: the maximum number of API calls to make in any
+	// RateLimitWindow; defaults to DefaultRateLimit
+	RateLimit int
+	// RateLimitWindow, optional: defaults to DefaultRateLimitWindow
+	RateLimitWindow time.Duration
+	// UserAgent, optional: the User-Agent sent with API calls to identify
+	// the integration; defaults to DefaultUserAgent
+	UserAgent string
+	// DebugBodies, optional: a writer which request and response bodies
+	// for REST calls are written to, with the access token redacted
+	DebugBodies io.Writer
+	// DebugBodyLimit, optional: the number of bytes of each body written
+	// to DebugBodies; defaults to DefaultDebugBodyLimit
+	DebugBodyLimit int
+	// RetryPolicy, optional: determines which REST calls are retried and
+	// the delay between attempts; defaults to DefaultRetryPolicy
+	RetryPolicy RetryPolicy
+	// Headers, optional: additional headers sent with every REST call,
+	// such as those required by a proxy or gateway. Headers set by the
+	// client, such as Authorization, are not overwritten.
+	Headers http.Header
+	// MaxIdleConnsPerHost, optional: the number of idle connections to
+	// Marketo kept open for reuse; defaults to that of
+	// http.DefaultTransport. Ignored if RESTTransport is set.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout, optional: how long idle connections are kept
+	// open; defaults to that of http.DefaultTransport. Ignored if
+	// RESTTransport is set.
+	IdleConnTimeout time.Duration
+	// PathPrefix, optional: a path inserted between the Endpoint's host
+	// and every API path, including authentication, for routing calls
+	// through a gateway; for example "/marketo" results in calls to
+	// https://gateway.example/marketo/rest/v1/...
+	PathPrefix string
+	// Clock, optional: the source of the current time and timers, which
+	// tests may replace to control token expiry, backoff and polling;
+	// defaults to the system clock
+	Clock Clock
 }
 
-// NewClient returns a new Marketo Client
+// NewClient returns a new Marketo Client. The client authenticates before
+// returning, so invalid credentials or an unreachable endpoint result in an
+// error here rather than on the first API call.
 func NewClient(config ClientConfig) (*Client, error) {
 	// create two roundtrippers
 	aRT := authRoundTripper{
@@ -120,14 +249,64 @@ func NewClient(config ClientConfig) (*Client, error) {
 		clientSecret: config.Secret,
 		delegate:     config.AuthTransport,
 	}
+	restTransport := config.RESTTransport
+	if restTransport == nil && (config.MaxIdleConnsPerHost > 0 || config.IdleConnTimeout > 0) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if config.MaxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+			if transport.MaxIdleConns < config.MaxIdleConnsPerHost {
+				transport.MaxIdleConns = config.MaxIdleConnsPerHost
+			}
+		}
+		if config.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = config.IdleConnTimeout
+		}
+		restTransport = transport
+	}
 	rRT := restRoundTripper{
-		delegate: config.RESTTransport,
+		delegate:  restTransport,
+		userAgent: config.UserAgent,
+		headers:   config.Headers,
+	}
+	if rRT.userAgent == "" {
+		rRT.userAgent = DefaultUserAgent
+	}
+	if config.DebugBodies != nil {
+		limit := config.DebugBodyLimit
+		if limit == 0 {
+			limit = DefaultDebugBodyLimit
+		}
+		rRT.delegate = &debugRoundTripper{
+			delegate: restTransport,
+			w:        config.DebugBodies,
+			limit:    limit,
+		}
 	}
 
 	timeout := config.Timeout
 	if timeout == 0 {
 		timeout = DefaultTimeout
 	}
+	rateLimit := config.RateLimit
+	if rateLimit == 0 {
+		rateLimit = DefaultRateLimit
+	}
+	rateLimitWindow := config.RateLimitWindow
+	if rateLimitWindow == 0 {
+		rateLimitWindow = DefaultRateLimitWindow
+	}
+	retryPolicy := config.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = DefaultRetryPolicy
+	}
+	clock := config.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	endpoint := strings.TrimSuffix(config.Endpoint, "/")
+	if prefix := strings.Trim(config.PathPrefix, "/"); prefix != "" {
+		endpoint += "/" + prefix
+	}
 	// Add credentials to the request
 	c := &Client{
 		authClient: &http.Client{
@@ -138,33 +317,92 @@ func NewClient(config ClientConfig) (*Client, error) {
 			Timeout:   time.Second * time.Duration(timeout),
 			Transport: &rRT,
 		},
-		restRoundTripper: &rRT,
-		endpoint:         config.Endpoint,
-		identityEndpoint: config.Endpoint + identityBase + identityPath,
-		debug:            config.Debug,
+		streamClient: &http.Client{
+			Transport: &rRT,
+		},
+		restRoundTripper:  &rRT,
+		endpoint:          endpoint,
+		identityEndpoint:  endpoint + identityBase + identityPath,
+		debug:             config.Debug,
+		requestTimeout:    config.DefaultRequestTimeout,
+		operationTimeouts: map[Operation]time.Duration{},
+		maxResponseBytes:  config.MaxResponseBytes,
+		retryPolicy:       retryPolicy,
+		limiter:           newRateLimiter(rateLimit, rateLimitWindow, clock),
+		tokenSource:       config.TokenSource,
+		clock:             clock,
 	}
 
-	if _, err := c.RefreshToken(); err != nil {
-		return nil, errors.New("Unable to refresh Marketo auth token")
+	if config.ConditionalRequests {
+		c.responseCache = newResponseCache()
+	}
+	for operation, timeout := range config.OperationTimeouts {
+		c.operationTimeouts[operation] = timeout
+	}
+
+	if _, err := c.refreshToken(context.Background()); err != nil {
+		return nil, err
 	}
 	return c, nil
 }
 
-// RefreshToken refreshes the auth token.
-// This is purely for testing purpose and not intended to be used.
-func (c *Client) RefreshToken() (auth AuthToken, err error) {
+// RefreshToken fetches a new access token, replacing the current one. Tokens
+// are refreshed automatically as they expire; this allows a token to be
+// fetched ahead of time, for example before a burst of calls.
+func (c *Client) RefreshToken(ctx context.Context) error {
+	_, err := c.refreshToken(ctx)
+	return err
+}
+
+// TokenExpiry returns the time at which the current access token expires
+func (c *Client) TokenExpiry() time.Time {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+	return c.tokenExpiresAt
+}
+
+func (c *Client) refreshToken(ctx context.Context) (auth AuthToken, err error) {
 	if c.debug {
 		log.Printf("[marketo/RefreshToken] start")
 		defer func() {
 			log.Print("[marketo/RefreshToken] DONE")
 		}()
 	}
-	// Make request for token
-	resp, err := c.authClient.Get(c.identityEndpoint)
+	if c.tokenSource != nil {
+		auth, err = c.tokenSource.Token(withClock(ctx, c.clock))
+		if err != nil {
+			return auth, fmt.Errorf("unable to get access token: %w", err)
+		}
+	} else {
+		auth, err = c.fetchToken(ctx)
+		if err != nil {
+			return auth, err
+		}
+	}
+	if c.debug {
+		log.Printf("[marketo/RefreshToken] New token: %v", auth)
+	}
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+	c.auth = &auth
+	c.restRoundTripper.setToken(auth.AccessToken)
+	c.tokenExpiresAt = c.clock.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	c.recordTokenRefresh()
+	return auth, nil
+}
+
+// fetchToken fetches a new access token from the identity endpoint using
+// the client credentials
+func (c *Client) fetchToken(ctx context.Context) (auth AuthToken, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.identityEndpoint, nil)
+	if err != nil {
+		return auth, err
+	}
+	resp, err := c.authClient.Do(req)
 	if err != nil {
 		return auth, errors.New("Unable to get Market auth token")
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 	if resp.StatusCode != 200 {
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
@@ -176,14 +414,6 @@ func (c *Client) RefreshToken() (auth AuthToken, err error) {
 	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
 		return auth, errors.New("Unable to decode marketo error token")
 	}
-	if c.debug {
-		log.Printf("[marketo/RefreshToken] New token: %v", auth)
-	}
-	c.authLock.Lock()
-	defer c.authLock.Unlock()
-	c.auth = &auth
-	c.restRoundTripper.token = auth.AccessToken
-	c.tokenExpiresAt = time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
 	return auth, nil
 }
 
@@ -199,19 +429,29 @@ func (c *Client) do(req *http.Request) (response *Response, err error) {
 			log.Printf("[marketo/do] DONE: body %s", string(body))
 		}()
 	}
+	if err = c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	start := c.clock.Now()
 	resp, err := c.restClient.Do(req)
 	if err != nil {
+		c.recordRequest(start, nil)
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.recordRequest(start, resp)
 
-	body, err = ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 	if resp.StatusCode != 200 {
+		body, err = ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		if err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("Unexpected status code[%d] with body[%s]", resp.StatusCode, string(body))
 	}
+	body, err = ioutil.ReadAll(c.limitBody(resp.Body))
+	if err != nil {
+		return nil, err
+	}
 
 	if len(body) == 0 {
 		return nil, fmt.Errorf("No body! Check URL: %s", req.URL)
@@ -225,11 +465,11 @@ func (c *Client) do(req *http.Request) (response *Response, err error) {
 
 func (c *Client) doWithRetry(req *http.Request) (response *Response, err error) {
 	// check if token has been expired or not
-	if c.tokenExpiresAt.Before(time.Now()) {
+	if expiry := c.TokenExpiry(); expiry.Before(c.clock.Now()) {
 		if c.debug {
-			log.Printf("[marketo/doWithRetry] token expired at: %s", c.tokenExpiresAt.String())
+			log.Printf("[marketo/doWithRetry] token expired at: %s", expiry.String())
 		}
-		c.RefreshToken()
+		c.refreshToken(req.Context())
 	}
 
 	response, err = c.do(req)
@@ -243,38 +483,262 @@ func (c *Client) doWithRetry(req *http.Request) (response *Response, err error)
 		return nil, err
 	}
 	if retry {
+		if err = rewind(req); err != nil {
+			return nil, err
+		}
 		response, err = c.do(req)
 	}
 
 	return response, err
 }
 
-func (c *Client) doRequest(req *http.Request) (response *http.Response, err error) {
+// streamingOperations are the operations whose response bodies are streamed
+// to the caller, and may take any amount of time to read. They are sent
+// without the client's Timeout, and DefaultRequestTimeout is not applied.
+var streamingOperations = map[string]bool{
+	getExportFile: true,
+}
+
+// doRequest sends req, refreshing the access token and retrying as needed.
+// The operation is used to select the timeout applied to the call.
+func (c *Client) doRequest(req *http.Request, operation string) (response *http.Response, err error) {
+	if c.debug {
+		log.Printf("[marketo/doRequest] %s%s %s", logPrefix(req.Context()), req.Method, req.URL)
+	}
 	// check if token has been expired or not
-	if c.tokenExpiresAt.Before(time.Now()) {
+	if expiry := c.TokenExpiry(); expiry.Before(c.clock.Now()) {
 		if c.debug {
-			log.Printf("[marketo/doWithRetry] token expired at: %s", c.tokenExpiresAt.String())
+			log.Printf("[marketo/doRequest] %stoken expired at: %s", logPrefix(req.Context()), expiry.String())
 		}
-		c.RefreshToken()
+		c.refreshToken(req.Context())
+	}
+
+	client, timeout := c.restClient, c.requestTimeout
+	if streamingOperations[operation] {
+		client, timeout = c.streamClient, 0
+	}
+	if t, ok := c.operationTimeouts[Operation(operation)]; ok {
+		timeout = t
+	}
+	cancel := context.CancelFunc(func() {})
+	if _, ok := req.Context().Deadline(); !ok && timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), timeout)
+		req = req.WithContext(ctx)
 	}
 
-	response, err = c.restClient.Do(req)
+	canRetry, err := replayable(req)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	conditional := c.responseCache != nil && req.Method == http.MethodGet && conditionalOperations[operation]
+	if conditional {
+		c.responseCache.prepare(req)
+	}
+
+	attempt, skipped := 1, false
+	for ; ; attempt++ {
+		if err = c.limiter.Wait(req.Context()); err != nil {
+			cancel()
+			return nil, err
+		}
+
+		var body []byte
+		start := c.clock.Now()
+		response, err = client.Do(req)
+		if err == nil && isJSON(response) {
+			// buffer the body so the retry policy can inspect it
+			body, err = ioutil.ReadAll(c.limitBody(response.Body))
+			response.Body.Close()
+			response.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		if err == nil {
+			c.recordRequest(start, response)
+		} else {
+			c.recordRequest(start, nil)
+		}
+
+		retry, delay := c.retryPolicy(response, err, attempt)
+		if body != nil {
+			response.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		if !retry {
+			break
+		}
+		if requested, ok := retryAfter(response, c.clock.Now()); ok {
+			delay = requested
+			if delay > MaximumRetryAfter {
+				delay = MaximumRetryAfter
+			}
+		}
+		if !canRetry {
+			if c.debug {
+				log.Printf("[marketo/doRequest] %snot retrying: request body exceeds %d bytes and cannot be replayed",
+					logPrefix(req.Context()), MaximumReplayBodySize)
+			}
+			skipped = true
+			break
+		}
+		if response != nil {
+			if hasReason(ResponseReasons(response), ErrAccessTokenInvalid, ErrAccessTokenExpired) {
+				if _, err := c.refreshToken(req.Context()); err != nil {
+					cancel()
+					return nil, err
+				}
+			}
+			drainAndClose(response.Body)
+		}
+		if c.debug {
+			log.Printf("[marketo/doRequest] %sretrying in %s after attempt %d", logPrefix(req.Context()), delay, attempt)
+		}
+
+		if err := sleep(req.Context(), c.clock, delay); err != nil {
+			cancel()
+			return nil, err
+		}
+		if err := rewind(req); err != nil {
+			cancel()
+			return nil, err
+		}
+		c.recordRetry()
+	}
+	if err != nil {
+		cancel()
+		return nil, &RequestError{Attempts: attempt, NotReplayable: skipped, Err: err}
+	}
+	if conditional {
+		if response, err = c.responseCache.update(req, response); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	response.Body = &responseBody{
+		ReadCloser:    response.Body,
+		cancel:        cancel,
+		attempts:      attempt,
+		notReplayable: skipped,
+	}
 
 	return response, err
 }
 
+// doResult performs an API request, decoding the result of a successful
+// response into result. Numbers decoded into interface{} values are
+// json.Number. The response envelope is returned so callers can
+// inspect paging tokens and warnings.
+func (c *Client) doResult(req *http.Request, operation string, result interface{}) (*Response, error) {
+	resp, err := c.doRequest(req, operation)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, handleError(operation, resp)
+	}
+
+	response := &Response{}
+	reader := json.NewDecoder(c.limitBody(resp.Body))
+	err = reader.Decode(response)
+	if err != nil {
+		return nil, err
+	}
+	if err = responseError(resp, response); err != nil {
+		return nil, err
+	}
+	if len(response.Warnings) > 0 {
+		if c.debug {
+			log.Printf("[marketo/doResult] %s%s warnings: %v", logPrefix(req.Context()), operation, response.Warnings)
+		}
+		if handler := warningHandler(req.Context()); handler != nil {
+			handler(operation, response.Warnings)
+		}
+	}
+
+	if result != nil && len(response.Result) > 0 {
+		// numbers decoded into interface{} values are kept as json.Number
+		// so that large integers and decimals are not rounded
+		decoder := json.NewDecoder(bytes.NewReader(response.Result))
+		decoder.UseNumber()
+		err = decoder.Decode(result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// responseBody wraps a response body, tracking the number of attempts made
+// to receive the response and whether a retry was skipped because the
+// request body could not be replayed, and cancelling the request context
+// once the body has been closed.
+type responseBody struct {
+	io.ReadCloser
+	cancel        context.CancelFunc
+	attempts      int
+	notReplayable bool
+}
+
+func (b *responseBody) Close() error {
+	defer b.cancel()
+	return drainAndClose(b.ReadCloser)
+}
+
+// ErrResponseTooLarge is returned when a response body exceeds the client's
+// MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// limitBody returns body limited to the client's maxResponseBytes, if set
+func (c *Client) limitBody(body io.Reader) io.Reader {
+	if c.maxResponseBytes <= 0 {
+		return body
+	}
+	return &maxBytesReader{r: body, remaining: c.maxResponseBytes}
+}
+
+// maxBytesReader reads from r, returning ErrResponseTooLarge once more than
+// the permitted number of bytes have been read
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// read one byte more than permitted to detect a body which is too large
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	if int64(n) > m.remaining {
+		n = int(m.remaining)
+		m.remaining = -1
+		return n, ErrResponseTooLarge
+	}
+	m.remaining -= int64(n)
+	return n, err
+}
+
+// drainAndClose reads any unread portion of body, up to maxDrainBytes, before
+// closing it, so that the connection can be reused; larger remainders, such
+// as the rest of an abandoned export file, are discarded with the
+// connection.
+func drainAndClose(body io.ReadCloser) error {
+	io.Copy(ioutil.Discard, io.LimitReader(body, maxDrainBytes))
+	return body.Close()
+}
+
 func (c *Client) checkToken(response *Response) (retry bool, err error) {
 	if len(response.Errors) > 0 && (response.Errors[0].Code == "601" || response.Errors[0].Code == "602") {
 		retry = true
 		if c.debug {
 			log.Printf("[marketo/checkToken] Expired/invalid token: %s", response.Errors[0].Code)
 		}
-		_, err = c.RefreshToken()
+		_, err = c.refreshToken(context.Background())
 	}
-	return retry, errors.New("Invalid/Expired Marketo Auth Token")
+	return retry, err
 }
 
 // Get performs an HTTP GET for the specified resource url
@@ -326,15 +790,43 @@ func (c *Client) Delete(resource string, data []byte) (response *Response, err e
 	return c.doWithRetry(req)
 }
 
+// Ping verifies that the client can authenticate and reach the REST API,
+// returning nil on success. It makes a single call to the usage stats
+// endpoint, which returns a small response.
+func (c *Client) Ping(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet, c.url("rest", "v1", "stats", "usage.json"), nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.doResult(request, ping, nil)
+	return err
+}
+
 // TokenInfo holds authentication token and time at which expires.
 type TokenInfo struct {
 	// Token is the currently active token.
 	Token string
 	// Expires shows what time the token expires
 	Expires time.Time
+	// TokenType is the type of token issued, typically "bearer"
+	TokenType string
+	// Scope identifies the API user the token was issued for. Marketo
+	// grants permissions through the user's role rather than the token,
+	// so calls the role does not permit fail with ErrAccessDenied.
+	Scope string
 }
 
 // GetTokenInfo returns current TokenInfo stored in Client
 func (c *Client) GetTokenInfo() TokenInfo {
-	return TokenInfo{c.auth.AccessToken, c.tokenExpiresAt}
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+	return TokenInfo{
+		Token:     c.auth.AccessToken,
+		Expires:   c.tokenExpiresAt,
+		TokenType: c.auth.TokenType,
+		Scope:     c.auth.Scope,
+	}
 }
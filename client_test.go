@@ -1,12 +1,17 @@
 package marketo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -205,16 +210,21 @@ func TestRefreshToken(t *testing.T) {
 	}
 
 	// refresh token
-	authToken, err := client.RefreshToken()
+	before := client.TokenExpiry()
+	err = client.RefreshToken(context.Background())
 	if err != nil {
 		t.Error(err)
 	}
+	if client.TokenExpiry().Before(before) {
+		t.Errorf("Expected expiry to advance from %s, got %s", before, client.TokenExpiry())
+	}
+	authToken := client.GetTokenInfo()
 
-	if authToken.AccessToken != tokens[1] {
-		t.Errorf("Expected %s to equal %s", tokens[1], authToken.AccessToken)
+	if authToken.Token != tokens[1] {
+		t.Errorf("Expected %s to equal %s", tokens[1], authToken.Token)
 	}
-	if authToken.ExpiresIn != 3599 {
-		t.Errorf("Expected 3599 to equal %d", authToken.ExpiresIn)
+	if d := time.Until(authToken.Expires); d < 3590*time.Second || d > 3599*time.Second {
+		t.Errorf("Expected token to expire in 3599 seconds, got %s", d)
 	}
 	if authToken.TokenType != "bearer" {
 		t.Errorf("Expected 'bearer' to equal %s", authToken.TokenType)
@@ -829,3 +839,604 @@ func TestPostSuccess(t *testing.T) {
 		t.Errorf("Expected only two calls: %d", called)
 	}
 }
+
+func TestDefaultRequestTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/identity/oauth/token" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	config := ClientConfig{
+		ID:                    clientID,
+		Secret:                clientSecret,
+		Endpoint:              ts.URL,
+		DefaultRequestTimeout: 50 * time.Millisecond,
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = NewLeadAPI(client).DescribeFields(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected request to time out early, took %s", time.Since(start))
+	}
+}
+
+func TestOperationTimeouts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/identity/oauth/token" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(getResponseSuccess))
+	}))
+	defer ts.Close()
+
+	config := ClientConfig{
+		ID:                    clientID,
+		Secret:                clientSecret,
+		Endpoint:              ts.URL,
+		DefaultRequestTimeout: 50 * time.Millisecond,
+		OperationTimeouts:     map[Operation]time.Duration{OperationPing: time.Second},
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.Ping(context.Background()); err != nil {
+		t.Errorf("Expected the operation timeout to apply, got %v", err)
+	}
+	_, err = NewLeadAPI(client).DescribeFields(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	for _, tc := range []struct {
+		configured string
+		expected   string
+	}{
+		{"", "go-marketo/" + Version},
+		{"my-integration/1.0", "my-integration/1.0"},
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			if r.URL.EscapedPath() == "/identity/oauth/token" {
+				w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+				return
+			}
+			if r.UserAgent() != tc.expected {
+				t.Errorf("Expected User-Agent %s, got %s", tc.expected, r.UserAgent())
+			}
+			w.Write([]byte(getResponseSuccess))
+		}))
+
+		config := ClientConfig{
+			ID:        clientID,
+			Secret:    clientSecret,
+			Endpoint:  ts.URL,
+			UserAgent: tc.configured,
+		}
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = client.Get(findLeadPath); err != nil {
+			t.Error(err)
+		}
+		ts.Close()
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.URL.EscapedPath() == "/identity/oauth/token" {
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+			return
+		}
+		if r.Header.Get("X-Gateway-Key") != "gateway" {
+			t.Errorf("Expected X-Gateway-Key gateway, got %s", r.Header.Get("X-Gateway-Key"))
+		}
+		if r.Header.Get("X-Tenant") != "acme" {
+			t.Errorf("Expected X-Tenant acme, got %s", r.Header.Get("X-Tenant"))
+		}
+		if auth := r.Header.Values("Authorization"); len(auth) != 1 || auth[0] != "Bearer "+token {
+			t.Errorf("Expected Authorization to be the bearer token, got %v", auth)
+		}
+		w.Write([]byte(getResponseSuccess))
+	}))
+	defer ts.Close()
+
+	config := ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: ts.URL,
+		Headers: http.Header{
+			"X-Gateway-Key": []string{"gateway"},
+			"Authorization": []string{"Basic overridden"},
+		},
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithHeader(context.Background(), "X-Tenant", "acme")
+	if err = client.Ping(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusOK)
+		if r.URL.EscapedPath() == "/marketo/identity/oauth/token" {
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+			return
+		}
+		w.Write([]byte(getResponseSuccess))
+	}))
+	defer ts.Close()
+
+	config := ClientConfig{
+		ID:         clientID,
+		Secret:     clientSecret,
+		Endpoint:   ts.URL + "/",
+		PathPrefix: "marketo/",
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Ping(context.Background()); err != nil {
+		t.Error(err)
+	}
+	if len(paths) != 2 || paths[1] != "/marketo/rest/v1/stats/usage.json" {
+		t.Errorf("Expected calls to be prefixed with /marketo, got %v", paths)
+	}
+}
+
+func TestTransportTuning(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClientConfig{
+		ID:                  clientID,
+		Secret:              clientSecret,
+		Endpoint:            ts.URL,
+		MaxIdleConnsPerHost: 50,
+		IdleConnTimeout:     time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport, ok := client.restRoundTripper.delegate.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", client.restRoundTripper.delegate)
+	}
+	if transport.MaxIdleConnsPerHost != 50 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("Expected transport to be tuned, got %d idle conns, %s timeout",
+			transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
+
+func TestDrainAndClose(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("unread error details")}
+	if err := drainAndClose(body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.closed {
+		t.Error("Expected body to be closed")
+	}
+	if n, _ := body.Read(make([]byte, 1)); n != 0 {
+		t.Error("Expected body to be drained before closing")
+	}
+}
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestErrorPathsReuseConnections(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/identity/oauth/token" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+			return
+		}
+		switch r.URL.Query().Get("case") {
+		case "status":
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(strings.Repeat("<p>bad gateway</p>", 1000)))
+		case "decode":
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("not json " + strings.Repeat("x", 10000)))
+		}
+	}))
+	var lock sync.Mutex
+	conns := 0
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			lock.Lock()
+			conns++
+			lock.Unlock()
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	client, err := NewClient(ClientConfig{
+		ID:            clientID,
+		Secret:        clientSecret,
+		Endpoint:      ts.URL,
+		RESTTransport: &http.Transport{},
+		AuthTransport: &http.Transport{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		for _, c := range []string{"status", "decode"} {
+			request, err := http.NewRequest(http.MethodGet, client.url("rest", "v1", "leads.json?case="+c), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = client.doResult(request, "test", nil); err == nil {
+				t.Errorf("Expected %s to fail", c)
+			}
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	// one connection for authentication, and one for the REST calls
+	if conns != 2 {
+		t.Errorf("Expected connections to be reused, got %d connections", conns)
+	}
+}
+
+func TestStaticToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/identity/oauth/token" {
+			t.Error("Expected the identity endpoint not to be called")
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer external-token" {
+			t.Errorf("Expected the external token, got %s", auth)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(getResponseSuccess))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClientConfig{
+		Endpoint:    ts.URL,
+		TokenSource: StaticToken("external-token"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Ping(context.Background()); err != nil {
+		t.Error(err)
+	}
+	if info := client.GetTokenInfo(); info.Token != "external-token" {
+		t.Errorf("Expected token info to report the external token, got %s", info.Token)
+	}
+}
+
+func TestTokenSourceError(t *testing.T) {
+	_, err := NewClient(ClientConfig{
+		Endpoint: "https://marketo.testing",
+		TokenSource: TokenSourceFunc(func(ctx context.Context) (AuthToken, error) {
+			return AuthToken{}, errors.New("credential service unavailable")
+		}),
+	})
+	if err == nil || err.Error() != "unable to get access token: credential service unavailable" {
+		t.Errorf("Expected the token source error, got %v", err)
+	}
+}
+
+func TestExpiringToken(t *testing.T) {
+	var seen []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(getResponseSuccess))
+	}))
+	defer ts.Close()
+
+	issued := 0
+	client, err := NewClient(ClientConfig{
+		Endpoint: ts.URL,
+		TokenSource: ExpiringToken(func() (string, time.Time, error) {
+			issued++
+			return fmt.Sprintf("token-%d", issued), time.Now().Add(time.Hour), nil
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err = client.Ping(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}
+	if len(seen) != 2 || seen[0] != "Bearer token-2" || seen[1] != "Bearer token-3" {
+		t.Errorf("Expected the token source to be called before each call, got %v", seen)
+	}
+
+	expired := ExpiringToken(func() (string, time.Time, error) {
+		return "stale", time.Now().Add(-time.Minute), nil
+	})
+	if _, err = expired.Token(context.Background()); err == nil {
+		t.Error("Expected an expired token to be rejected")
+	}
+
+	// expiry is checked against the clock of the calling client
+	clock := &fakeClock{now: time.Now().Add(2 * time.Hour)}
+	_, err = NewClient(ClientConfig{
+		Endpoint: ts.URL,
+		TokenSource: ExpiringToken(func() (string, time.Time, error) {
+			return "token", time.Now().Add(time.Hour), nil
+		}),
+		Clock: clock,
+	})
+	if err == nil {
+		t.Error("Expected a token expired by the client's clock to be rejected")
+	}
+}
+
+// fakeClock is a Clock whose time only moves when advanced, either
+// explicitly or by creating a timer, which fires immediately unless the
+// clock is frozen.
+type fakeClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	frozen bool
+	delays []time.Duration
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	c       chan time.Time
+	stopped bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	stopped := t.stopped
+	t.stopped = true
+	return !stopped && len(t.c) == 0
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.delays = append(c.delays, d)
+	timer := &fakeTimer{c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	if !c.frozen {
+		c.now = c.now.Add(d)
+		timer.c <- c.now
+	}
+	return timer
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestClock(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(getResponseSuccess))
+	}))
+	defer ts.Close()
+
+	tokens := 0
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client, err := NewClient(ClientConfig{
+		Endpoint: ts.URL,
+		TokenSource: TokenSourceFunc(func(ctx context.Context) (AuthToken, error) {
+			tokens++
+			return AuthToken{AccessToken: fmt.Sprintf("token-%d", tokens), ExpiresIn: 3600}, nil
+		}),
+		Clock: clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := clock.Now().Add(time.Hour); !client.TokenExpiry().Equal(expected) {
+		t.Errorf("Expected token to expire at %s, got %s", expected, client.TokenExpiry())
+	}
+
+	if err = client.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(clock.delays) != 1 || clock.delays[0] != DefaultRetryBackoff {
+		t.Errorf("Expected a single backoff of %s, got %v", DefaultRetryBackoff, clock.delays)
+	}
+	if tokens != 1 {
+		t.Errorf("Expected the token not to be refreshed, got %d tokens", tokens)
+	}
+
+	clock.Advance(time.Hour)
+	if err = client.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if tokens != 2 {
+		t.Errorf("Expected the expired token to be refreshed, got %d tokens", tokens)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	for _, contentType := range []string{"application/json", "text/plain"} {
+		t.Run(contentType, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() == "/identity/oauth/token" {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+					return
+				}
+				w.Header().Set("Content-Type", contentType)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"success":true,"result":[` + strings.Repeat(`{},`, 1000) + `{}]}`))
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ClientConfig{
+				ID:               clientID,
+				Secret:           clientSecret,
+				Endpoint:         ts.URL,
+				MaxResponseBytes: 1024,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = client.Ping(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+				t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+			}
+
+			client.maxResponseBytes = 1 << 20
+			if err = client.Ping(context.Background()); err != nil {
+				t.Errorf("Expected the response to be read within the limit, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetrySendsFreshToken(t *testing.T) {
+	var seen [][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Values("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if len(seen) == 1 {
+			w.Write([]byte(`{"success":false,"errors":[{"code":"601","message":"Access token invalid"}]}`))
+			return
+		}
+		w.Write([]byte(getResponseSuccess))
+	}))
+	defer ts.Close()
+
+	tokens := 0
+	client, err := NewClient(ClientConfig{
+		Endpoint: ts.URL,
+		TokenSource: TokenSourceFunc(func(ctx context.Context) (AuthToken, error) {
+			tokens++
+			return AuthToken{AccessToken: fmt.Sprintf("tok%d", tokens), ExpiresIn: 3600}, nil
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != 2 {
+		t.Fatalf("Expected the call to be retried once, got %d requests", len(seen))
+	}
+	if len(seen[0]) != 1 || seen[0][0] != "Bearer tok1" {
+		t.Errorf("Expected a single original token, got %v", seen[0])
+	}
+	if len(seen[1]) != 1 || seen[1][0] != "Bearer tok2" {
+		t.Errorf("Expected a single refreshed token on retry, got %v", seen[1])
+	}
+}
+
+func TestLargeErrorResponse(t *testing.T) {
+	page := "<html>" + strings.Repeat("bad gateway ", 1<<20) + "</html>"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/identity/oauth/token" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: ts.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Ping(context.Background())
+	var e Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected an Error, got %v", err)
+	}
+	if e.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected status %d, got %d", http.StatusBadGateway, e.StatusCode)
+	}
+	if len(e.Body) != maxErrorBodyBytes {
+		t.Errorf("Expected the error body to be truncated to %d bytes, got %d", maxErrorBodyBytes, len(e.Body))
+	}
+
+	_, err = client.Get("/rest/v1/leads.json")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(err.Error()) > 2*maxErrorBodyBytes {
+		t.Errorf("Expected a truncated error, got %d bytes", len(err.Error()))
+	}
+}
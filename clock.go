@@ -0,0 +1,76 @@
+package marketo
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the current time and timers to the client. Token expiry,
+// retry backoff, rate limiting and import polling all use the client's
+// Clock, so tests can substitute one they control. The clock is also passed
+// to token sources and import stores through the context of their calls.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// NewTimer returns a Timer which fires once d has elapsed
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event created by a Clock, like time.Timer
+type Timer interface {
+	// C returns the channel which receives the time when the timer fires
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, returning false if it has
+	// already fired or been stopped
+	Stop() bool
+}
+
+// realClock is the Clock used by default, backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{t: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time {
+	return r.t.C
+}
+
+func (r realTimer) Stop() bool {
+	return r.t.Stop()
+}
+
+// sleep waits for d to elapse on clock, returning early with the context's
+// error if ctx is done first
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// withClock returns a copy of ctx carrying clock, for the token sources and
+// import stores called by the client
+func withClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey, clock)
+}
+
+// contextClock returns the Clock carried by ctx, or the system clock
+func contextClock(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey).(Clock); ok {
+		return clock
+	}
+	return realClock{}
+}
@@ -0,0 +1,102 @@
+package marketo
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// conditionalOperations are the operations whose responses are cached and
+// revalidated when ConditionalRequests is enabled. Describe results change
+// rarely and are fetched often by schema tooling.
+var conditionalOperations = map[string]bool{
+	describeLead2:              true,
+	describeCustomObject:       true,
+	describeCustomObjectSchema: true,
+}
+
+// cachedResponse is a response stored along with its validators
+type cachedResponse struct {
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+// responseCache stores responses which included an ETag or Last-Modified
+// header, keyed by URL, so that later requests for the same URL can be made
+// conditional.
+type responseCache struct {
+	lock    sync.Mutex
+	entries map[string]cachedResponse
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: map[string]cachedResponse{}}
+}
+
+// prepare adds the validators of any cached response for req's URL to req
+func (c *responseCache) prepare(req *http.Request) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[req.URL.String()]
+	if !ok {
+		return
+	}
+	if entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// update returns the response to use for req: the cached response if resp
+// indicates it has not been modified, or resp itself, which is stored if it
+// includes validators.
+func (c *responseCache) update(req *http.Request, resp *http.Response) (*http.Response, error) {
+	key := req.URL.String()
+	if resp.StatusCode == http.StatusNotModified {
+		c.lock.Lock()
+		entry, ok := c.entries[key]
+		c.lock.Unlock()
+		if !ok {
+			return resp, nil
+		}
+		drainAndClose(resp.Body)
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        entry.header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(entry.body)),
+			ContentLength: int64(len(entry.body)),
+			Request:       req,
+		}, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries[key] = cachedResponse{
+		etag:         etag,
+		lastModified: lastModified,
+		header:       resp.Header.Clone(),
+		body:         body,
+	}
+	return resp, nil
+}
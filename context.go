@@ -0,0 +1,72 @@
+package marketo
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey int
+
+const (
+	correlationIDKey contextKey = iota
+	headersKey
+	warningHandlerKey
+	clockKey
+)
+
+// WithCorrelationID returns a copy of ctx carrying the provided correlation
+// ID. The ID is not sent to Marketo; it is included in the client's debug
+// output for calls made with the context, so related operations can be tied
+// together in logs.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, if any.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// logPrefix returns the prefix used for debug output of calls made with ctx
+func logPrefix(ctx context.Context) string {
+	if id := CorrelationID(ctx); id != "" {
+		return "[" + id + "] "
+	}
+	return ""
+}
+
+// WithHeader returns a copy of ctx carrying an additional header to send with
+// REST calls made using the context, such as a key required by a gateway.
+// Headers set by the client, such as Authorization, are not overwritten.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	headers := contextHeaders(ctx).Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Add(key, value)
+	return context.WithValue(ctx, headersKey, headers)
+}
+
+// contextHeaders returns the headers carried by ctx, if any
+func contextHeaders(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(headersKey).(http.Header)
+	return headers
+}
+
+// WarningHandler is called with the warnings Marketo returns for a
+// successful operation, such as fields which were ignored.
+type WarningHandler func(operation string, warnings []Reason)
+
+// WithWarningHandler returns a copy of ctx which calls handler with any
+// warnings returned by calls made using the context. Warnings are otherwise
+// only included in the client's debug output.
+func WithWarningHandler(ctx context.Context, handler WarningHandler) context.Context {
+	return context.WithValue(ctx, warningHandlerKey, handler)
+}
+
+// warningHandler returns the WarningHandler carried by ctx, if any
+func warningHandler(ctx context.Context) WarningHandler {
+	handler, _ := ctx.Value(warningHandlerKey).(WarningHandler)
+	return handler
+}
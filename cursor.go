@@ -0,0 +1,62 @@
+package marketo
+
+import (
+	"context"
+	"errors"
+)
+
+// Cursor pages through the results of a custom object filter query. A
+// Cursor can be serialized to JSON and, once passed to
+// CustomObjects.Resume, continues from the page after the last one
+// returned, allowing a long-running read to resume after a restart.
+type Cursor struct {
+	Object string `json:"object"`
+	Query  Query  `json:"query"`
+	// Done is true once the final page has been returned
+	Done bool `json:"done"`
+
+	api *CustomObjects
+}
+
+// NewCursor returns a Cursor over the records of the provided custom object
+// matching the query options.
+func (c *CustomObjects) NewCursor(name string, opts ...QueryOption) *Cursor {
+	cursor := &Cursor{
+		Object: name,
+		api:    c,
+	}
+	for _, opt := range opts {
+		opt(&cursor.Query)
+	}
+	return cursor
+}
+
+// Resume associates a deserialized Cursor with the API, so that it can be
+// used to retrieve further pages.
+func (c *CustomObjects) Resume(cursor *Cursor) *Cursor {
+	cursor.api = c
+	return cursor
+}
+
+// Next returns the next page of results, or nil once there are no more. A
+// deserialized Cursor must be passed to CustomObjects.Resume before Next is
+// called.
+func (cur *Cursor) Next(ctx context.Context) ([]CustomObjectResult, error) {
+	if cur.Done {
+		return nil, nil
+	}
+	if cur.api == nil {
+		return nil, errors.New("cursor is not associated with an API; pass it to CustomObjects.Resume")
+	}
+
+	query := cur.Query
+	results, next, err := cur.api.Filter(ctx, cur.Object, func(q *Query) {
+		*q = query
+	})
+	if err != nil {
+		return nil, err
+	}
+	cur.Query.NextPageToken = next
+	cur.Done = next == ""
+	return results, nil
+}
@@ -0,0 +1,84 @@
+package marketo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCursor(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			return r.PostForm.Get("nextPageToken") == "", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"PAGE2","result":[{"seq":0,"marketoGUID":"a"}]}`)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "email", r.PostForm.Get("filterType"))
+			return r.PostForm.Get("nextPageToken") == "PAGE2", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"marketoGUID":"b"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	cursor := api.NewCursor("testObject_c",
+		FilterField("email"),
+		FilterValues([]string{"nathan@polytomic.com"}),
+	)
+	page, err := cursor.Next(context.Background())
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "a", page[0].MarketoGUID)
+
+	// resume from a serialized cursor
+	saved, err := json.Marshal(cursor)
+	require.NoError(t, err)
+	restored := &Cursor{}
+	require.NoError(t, json.Unmarshal(saved, restored))
+	api.Resume(restored)
+
+	page, err = restored.Next(context.Background())
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "b", page[0].MarketoGUID)
+	assert.True(t, restored.Done)
+
+	page, err = restored.Next(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, page)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCursorNotResumed(t *testing.T) {
+	cursor := &Cursor{}
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"testObject_c","query":{"nextPageToken":"page2"}}`), cursor))
+
+	page, err := cursor.Next(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, page)
+}
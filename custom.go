@@ -1,12 +1,15 @@
 package marketo
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -32,10 +35,44 @@ type RelatedObject struct {
 	Name  string `json:"name"`
 }
 
+// RelationType describes how a custom object relates to another object
+type RelationType string
+
+const (
+	// RelationChild indicates the object is a child of the related object,
+	// such as a lead or company; each record links to one parent record
+	// through the relationship's field
+	RelationChild RelationType = "child"
+	// RelationParent indicates the object is the parent of the related
+	// object
+	RelationParent RelationType = "parent"
+)
+
+// UnmarshalJSON decodes a relation type, normalizing the known types
+// regardless of case. Unrecognized types are preserved as-is.
+func (t *RelationType) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid relation type %s: %w", data, err)
+	}
+	*t = RelationType(value)
+	for _, known := range []RelationType{RelationChild, RelationParent} {
+		if strings.EqualFold(value, string(known)) {
+			*t = known
+		}
+	}
+	return nil
+}
+
+// Known returns true if t is one of the relation types defined above
+func (t RelationType) Known() bool {
+	return t == RelationChild || t == RelationParent
+}
+
 type ObjectRelation struct {
 	Field     string        `json:"field"`
 	RelatedTo RelatedObject `json:"relatedTo"`
-	Type      string        `json:"type"`
+	Type      RelationType  `json:"type"`
 }
 
 type ObjectField struct {
@@ -46,7 +83,7 @@ type ObjectField struct {
 	Updateable  bool   `json:"updateable"`
 	CRMManaged  bool   `json:"crmManaged"`
 
-	Searchable bool `json:"searchable,omitEmpty"`
+	Searchable bool `json:"searchable,omitempty"`
 }
 
 type CustomObjectMetadata struct {
@@ -65,6 +102,48 @@ type CustomObjectMetadata struct {
 	Version          ObjectVersion    `json:"version"`
 }
 
+// Parent returns the relationship linking the object to its parent, such as
+// a lead, if it has one.
+func (m CustomObjectMetadata) Parent() (ObjectRelation, bool) {
+	for _, r := range m.Relationships {
+		if r.Type == RelationChild {
+			return r, true
+		}
+	}
+	return ObjectRelation{}, false
+}
+
+// LinkFields returns the fields linking the object to its parent records.
+// Each record imported must include them, with the value of the related
+// object's field (ObjectRelation.RelatedTo.Field) for the parent record, for
+// example the email address of the lead the record belongs to; records
+// without a valid link are not associated with a parent.
+func (m CustomObjectMetadata) LinkFields() []string {
+	var fields []string
+	for _, r := range m.Relationships {
+		if r.Type == RelationChild {
+			fields = append(fields, r.Field)
+		}
+	}
+	return fields
+}
+
+// RequiredImportColumns returns the columns an import file for the object
+// must contain: its dedupe fields and link fields. Pass them to
+// WithDedupeColumns to check an import before it is uploaded.
+func (m CustomObjectMetadata) RequiredImportColumns() []string {
+	columns := append([]string{}, m.DedupeFields...)
+	return append(columns, missing(m.LinkFields(), columns)...)
+}
+
+// ErrObjectNotFound is returned when describing an object which does not
+// exist.
+var ErrObjectNotFound = errors.New("object not found")
+
+// ErrMultipleObjects is returned by FilterOne when more than one record
+// matches the filter.
+var ErrMultipleObjects = errors.New("multiple objects found")
+
 // CustomObjectResult contains a single record returned when filtering custom
 // objects.
 type CustomObjectResult struct {
@@ -73,9 +152,68 @@ type CustomObjectResult struct {
 	Fields      map[string]interface{} `json:"-" mapstructure:",remain"`
 }
 
+// SyncAction defines how records are written when syncing custom objects
+type SyncAction string
+
+const (
+	SyncCreateOrUpdate SyncAction = "createOrUpdate"
+	SyncCreateOnly     SyncAction = "createOnly"
+	SyncUpdateOnly     SyncAction = "updateOnly"
+)
+
+// DedupeBy defines which key Marketo uses to match synced records to
+// existing ones
+type DedupeBy string
+
+const (
+	// DedupeByDedupeFields matches records using the object's dedupe
+	// fields, such as an external ID; each record must include them
+	DedupeByDedupeFields DedupeBy = "dedupeFields"
+	// DedupeByIDField matches records using the object's ID field,
+	// typically marketoGUID
+	DedupeByIDField DedupeBy = "idField"
+)
+
+// SyncResult contains the result of syncing a single custom object record.
+type SyncResult struct {
+	Sequence    int      `json:"seq"`
+	MarketoGUID string   `json:"marketoGUID,omitempty"`
+	Status      string   `json:"status"`
+	Reasons     []Reason `json:"reasons,omitempty"`
+}
+
+// DeleteResult contains the result of deleting a single custom object
+// record.
+type DeleteResult = SyncResult
+
+// HasReason returns true if the record was skipped for the provided reason,
+// for example ErrObjectAlreadyExists when creating a duplicate record.
+func (r SyncResult) HasReason(reason Reason) bool {
+	for _, rr := range r.Reasons {
+		if rr.Code == reason.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDuplicate returns true if the record was skipped because it would have
+// created a duplicate.
+func (r SyncResult) IsDuplicate() bool {
+	return r.HasReason(ErrObjectAlreadyExists) || r.HasReason(ErrDuplicateObjectInInput)
+}
+
 const (
-	describeCustomObject = "describe custom object"
-	listCustomObjects    = "list custom objects"
+	describeCustomObject       = "describe custom object"
+	describeCustomObjectSchema = "describe custom object schema"
+	listCustomObjects          = "list custom objects"
+	approveCustomObject        = "approve custom object"
+	discardCustomObjectDraft   = "discard custom object draft"
+	deleteCustomObjectField    = "delete custom object field"
+	deleteCustomObject         = "delete custom object"
+	syncCustomObjects          = "sync custom objects"
+	deleteCustomObjects        = "delete custom objects"
+	filterCustomObjects        = "filter custom objects"
 )
 
 // CustomObjects provides access to the Marketo custom objects API
@@ -88,135 +226,503 @@ func NewCustomObjectsAPI(c *Client) *CustomObjects {
 	return &CustomObjects{c}
 }
 
-// List returns the custom objects supported by the Marketo instance
+func (c *CustomObjects) object(name string) objectAPI {
+	return objectAPI{c: c.Client, path: "customobjects/" + escapeName(name)}
+}
+
+// escapeName escapes an object's API name for use as a single path segment,
+// so that characters such as "/", "?" or "#" are not interpreted as part of
+// the URL's structure
+func escapeName(name string) string {
+	return url.PathEscape(name)
+}
+
+// List returns the custom objects supported by the Marketo instance. Marketo
+// currently returns every object in a single response; should it return a
+// paging token, the remaining pages are fetched as well.
 func (c *CustomObjects) List(ctx context.Context) ([]CustomObjectMetadata, error) {
-	request, err := http.NewRequest(
-		http.MethodGet, c.url("rest", "v1", "customobjects.json"), nil,
+	objects := []CustomObjectMetadata{}
+	next := ""
+	for {
+		query := url.Values{}
+		if next != "" {
+			query.Set("nextPageToken", next)
+		}
+		endpoint := c.url("rest", "v1", "customobjects.json")
+		if len(query) > 0 {
+			endpoint += "?" + query.Encode()
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		page := []CustomObjectMetadata{}
+		response, err := c.doResult(request, listCustomObjects, &page)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page...)
+		if response.NextPageToken == "" || response.NextPageToken == next {
+			return objects, nil
+		}
+		next = response.NextPageToken
+	}
+}
+
+// Describe returns the description for the provided custom object
+func (c *CustomObjects) Describe(ctx context.Context, name string) (*CustomObjectMetadata, error) {
+	return c.describe(ctx, describeCustomObject,
+		c.url("rest", "v1", "customobjects", escapeName(name), "describe.json"),
 	)
+}
+
+// DescribeAll lists the instance's custom objects and describes each of them,
+// making up to concurrency calls at once, returning the descriptions keyed by
+// API name. Concurrency is limited to MaximumConcurrentCalls, the most
+// Marketo permits; calls remain subject to the client's rate limit.
+func (c *CustomObjects) DescribeAll(ctx context.Context, concurrency int) (map[string]CustomObjectMetadata, error) {
+	objects, err := c.List(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > MaximumConcurrentCalls {
+		concurrency = MaximumConcurrentCalls
+	}
 
-	resp, err := c.Client.doRequest(request)
-	if err != nil {
-		return nil, err
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := map[string]CustomObjectMetadata{}
+	var firstErr error
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, concurrency)
+	for _, object := range objects {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			object, err := c.Describe(ctx, name)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("describing %s: %w", name, err)
+					cancel()
+				}
+				return
+			}
+			result[name] = *object
+		}(object.APIName)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, handleError(listCustomObjects, resp)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
 	}
+	return result, nil
+}
 
-	response := &Response{}
-	reader := json.NewDecoder(resp.Body)
-	err = reader.Decode(response)
+// DescribeBoth returns the draft and approved versions of the provided custom
+// object's schema. If the object has never been approved, approved is nil;
+// if the object has no pending changes, draft is nil.
+func (c *CustomObjects) DescribeBoth(ctx context.Context, name string) (draft, approved *CustomObjectMetadata, err error) {
+	object, err := c.describe(ctx, describeCustomObjectSchema,
+		c.url("rest", "v1", "customobjects", "schema", escapeName(name), "describe.json"),
+	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	objects := []CustomObjectMetadata{}
-	err = json.Unmarshal(response.Result, &objects)
+	switch object.State {
+	case ObjectStateDraft:
+		return object, nil, nil
+	case ObjectStateApproved:
+		return nil, object, nil
+	}
 
-	return objects, err
+	// the object is approved with a pending draft; fetch the version we
+	// didn't receive
+	other := DraftVersion
+	if object.Version == DraftVersion {
+		other = ApprovedVersion
+	}
+	otherObject, err := c.describe(ctx, describeCustomObjectSchema,
+		c.url("rest", "v1", "customobjects", "schema", escapeName(name),
+			fmt.Sprintf("describe.json?state=%s", other)),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	if other == DraftVersion {
+		return otherObject, object, nil
+	}
+	return object, otherObject, nil
 }
 
-// Describe returns the description for the provided custom object
-func (c *CustomObjects) Describe(ctx context.Context, name string) (*CustomObjectMetadata, error) {
-	request, err := http.NewRequest(
-		http.MethodGet, c.url("rest", "v1", "customobjects", name, "describe.json"), nil,
-	)
+// describe performs a describe call against the provided endpoint
+func (c *CustomObjects) describe(ctx context.Context, operation, endpoint string) (*CustomObjectMetadata, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.Client.doRequest(request)
+	object := []CustomObjectMetadata{}
+	_, err = c.doResult(request, operation, &object)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, handleError(describeCustomObject, resp)
+	if len(object) == 0 {
+		return nil, ErrObjectNotFound
 	}
 
-	response := &Response{}
-	reader := json.NewDecoder(resp.Body)
-	err = reader.Decode(response)
+	object[0].Fields = markSearchable(object[0].Fields, object[0].SearchableFields)
+	return &object[0], err
+}
+
+// markSearchable sets Searchable on each of fields which is part of one of
+// the searchable keys, clearing it on the others, and returns fields
+func markSearchable(fields []ObjectField, searchable [][]string) []ObjectField {
+	names := searchableNames(searchable)
+	for i := range fields {
+		fields[i].Searchable = names[fields[i].Name]
+	}
+	return fields
+}
+
+// searchableNames returns the names of the fields which are part of any of
+// the searchable keys returned by a describe call
+func searchableNames(searchable [][]string) map[string]bool {
+	names := map[string]bool{}
+	for _, key := range searchable {
+		for _, field := range key {
+			names[field] = true
+		}
+	}
+	return names
+}
+
+// Filter queries Marketo for custom objects that match the provided filters.
+// Marketo only matches filter values exactly; the filter field must be one of
+// the object's searchable fields, and date ranges such as updatedAt are not
+// supported. Incremental sync by date requires the bulk export API.
+func (c *CustomObjects) Filter(ctx context.Context, name string, opts ...QueryOption) ([]CustomObjectResult, string, error) {
+	q := &Query{}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	raw, next, err := c.object(name).filter(ctx, filterCustomObjects, q)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	object := []CustomObjectMetadata{}
-	err = json.Unmarshal(response.Result, &object)
+	results := make([]CustomObjectResult, len(raw))
+	for i, l := range raw {
+		err = mapstructure.Decode(l, &results[i])
+		if err != nil {
+			return nil, "", err
+		}
+	}
 
-	if len(object) == 0 {
-		return nil, errors.New("not found")
+	return results, next, nil
+}
+
+// FilterOne returns the single custom object record matching the provided
+// filters, typically a unique key. ErrObjectNotFound is returned if no record
+// matches, and ErrMultipleObjects if more than one does.
+func (c *CustomObjects) FilterOne(ctx context.Context, name string, opts ...QueryOption) (*CustomObjectResult, error) {
+	results, next, err := c.Filter(ctx, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(results) == 0:
+		return nil, ErrObjectNotFound
+	case len(results) > 1 || next != "":
+		return nil, fmt.Errorf("%s: %w", name, ErrMultipleObjects)
+	}
+	return &results[0], nil
+}
+
+// Count returns the number of records matching the provided filters.
+// Marketo does not provide a count, so every page of matching records is
+// fetched, requesting only marketoGUID and the largest batch size unless
+// another is set; the cost is one call per page, as with FilterAll. If
+// WithMaxRecords is set, counting stops at that many records.
+func (c *CustomObjects) Count(ctx context.Context, name string, opts ...QueryOption) (int, error) {
+	q := &Query{}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if err := q.checkFilter(); err != nil {
+		return 0, err
+	}
+	q.Fields = []string{"marketoGUID"}
+	if q.BatchSize == 0 {
+		q.BatchSize = MaximumQueryBatchSize
 	}
 
-	searchable := map[string]bool{}
-	for _, s := range object[0].SearchableFields {
-		for _, fld := range s {
-			searchable[fld] = true
+	count := 0
+	for _, batch := range FilterBatches(q.FilterValues) {
+		query := *q
+		query.FilterValues = batch
+		for {
+			if query.MaxRecords > 0 && count >= query.MaxRecords {
+				return query.MaxRecords, nil
+			}
+			page, next, err := c.object(name).filter(ctx, filterCustomObjects, &query)
+			if err != nil {
+				return 0, err
+			}
+			count += len(page)
+			if next == "" {
+				break
+			}
+			query.NextPageToken = next
 		}
 	}
+	if q.MaxRecords > 0 && count > q.MaxRecords {
+		count = q.MaxRecords
+	}
+	return count, nil
+}
 
-	for i, field := range object[0].Fields {
-		field.Searchable = searchable[field.Name]
-		object[0].Fields[i] = field
+// GetByKey returns the records of the provided custom object whose keyField
+// matches any of keyValues, retrieving the listed fields. keyField must be
+// one of the object's single-field searchable keys. Values are queried in
+// batches of MaximumQueryBatchSize, and every page of results is returned.
+func (c *CustomObjects) GetByKey(ctx context.Context, name, keyField string, keyValues []string, fields []string) ([]CustomObjectResult, error) {
+	object, err := c.Describe(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	searchable := false
+	for _, key := range object.SearchableFields {
+		if len(key) == 1 && key[0] == keyField {
+			searchable = true
+			break
+		}
+	}
+	if !searchable {
+		return nil, fmt.Errorf("%s is not a searchable field of %s", keyField, name)
 	}
 
-	return &object[0], err
+	return c.FilterAll(ctx, name,
+		FilterField(keyField), FilterValues(keyValues), GetFields(fields...),
+	)
 }
 
-// Filter queries Marketo for custom objects that match the provided filters.
-func (c *CustomObjects) Filter(ctx context.Context, name string, opts ...QueryOption) ([]CustomObjectResult, string, error) {
+// GetByGUID returns the records of the provided custom object with the given
+// Marketo GUIDs, such as those returned by Sync, retrieving the listed
+// fields.
+func (c *CustomObjects) GetByGUID(ctx context.Context, name string, guids []string, fields []string) ([]CustomObjectResult, error) {
+	return c.FilterAll(ctx, name,
+		FilterField("marketoGUID"), FilterValues(guids), GetFields(fields...),
+	)
+}
+
+// FilterAll returns every record matching the provided filters, following
+// paging tokens. Filter values are queried in batches of
+// MaximumQueryBatchSize, so any number may be provided. If WithMaxRecords is
+// set, paging stops once that many records have been returned.
+func (c *CustomObjects) FilterAll(ctx context.Context, name string, opts ...QueryOption) ([]CustomObjectResult, error) {
 	q := &Query{}
 	for _, opt := range opts {
 		opt(q)
 	}
+	if err := q.checkFilter(); err != nil {
+		return nil, err
+	}
 
-	query, err := q.Values()
-	if err != nil {
-		return nil, "", err
+	results := []CustomObjectResult{}
+	for _, batch := range FilterBatches(q.FilterValues) {
+		query := *q
+		query.FilterValues = batch
+		for {
+			if query.MaxRecords > 0 {
+				remaining := query.MaxRecords - len(results)
+				if remaining <= 0 {
+					return results, nil
+				}
+				// avoid fetching records past the limit
+				if query.BatchSize == 0 || query.BatchSize > remaining {
+					query.BatchSize = remaining
+				}
+			}
+
+			page, next, err := c.Filter(ctx, name, func(pq *Query) {
+				*pq = query
+			})
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, page...)
+			if next == "" {
+				break
+			}
+			query.NextPageToken = next
+		}
 	}
-	request, err := http.NewRequest(
+	if q.MaxRecords > 0 && len(results) > q.MaxRecords {
+		results = results[:q.MaxRecords]
+	}
+	return results, nil
+}
+
+// Approve approves the current draft of the provided custom object's schema.
+func (c *CustomObjects) Approve(ctx context.Context, name string) error {
+	return c.schemaAction(ctx, approveCustomObject, name, "approve.json", nil)
+}
+
+// Discard discards the current draft of the provided custom object's schema,
+// leaving the approved version unchanged.
+func (c *CustomObjects) Discard(ctx context.Context, name string) error {
+	return c.schemaAction(ctx, discardCustomObjectDraft, name, "discardDraft.json", nil)
+}
+
+// DeleteField removes a field from the draft of the provided custom object's
+// schema; the deletion takes effect once the draft is approved.
+func (c *CustomObjects) DeleteField(ctx context.Context, name, field string) error {
+	input := map[string]interface{}{
+		"input": []map[string]string{
+			{"name": field},
+		},
+	}
+	return c.schemaAction(ctx, deleteCustomObjectField, name, "deleteField.json", input)
+}
+
+// DeleteObject deletes the provided custom object, including both its draft
+// and approved versions. This cannot be undone.
+func (c *CustomObjects) DeleteObject(ctx context.Context, name string) error {
+	return c.schemaAction(ctx, deleteCustomObject, name, "delete.json", nil)
+}
+
+// schemaAction performs a POST to the schema action endpoint for the provided
+// custom object, serializing input as the request body if provided.
+func (c *CustomObjects) schemaAction(ctx context.Context, operation, name, action string, input interface{}) error {
+	body := []byte{}
+	if input != nil {
+		var err error
+		body, err = json.Marshal(input)
+		if err != nil {
+			return err
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx,
 		http.MethodPost,
-		c.url("rest", "v1", "customobjects", fmt.Sprintf("%s.json?_method=GET", name)),
-		strings.NewReader(query.Encode()),
+		c.url("rest", "v1", "customobjects", "schema", escapeName(name), action),
+		bytes.NewReader(body),
 	)
-	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	if err != nil {
-		return nil, "", err
+		return err
 	}
+	request.Header.Add("Content-Type", "application/json")
 
-	resp, err := c.doRequest(request)
+	resp, err := c.Client.doRequest(request, operation)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, "", handleError(filterLeads, resp)
+		return handleError(operation, resp)
 	}
 
 	response := &Response{}
 	reader := json.NewDecoder(resp.Body)
 	err = reader.Decode(response)
 	if err != nil {
-		return nil, "", err
+		return err
+	}
+	return responseError(resp, response)
+}
+
+// Sync creates or updates custom object records. If dedupeBy is empty,
+// Marketo's default of DedupeByDedupeFields is used. Records skipped by
+// Marketo are reported in the corresponding SyncResult, along with the
+// reasons they were skipped.
+func (c *CustomObjects) Sync(ctx context.Context, name string, action SyncAction, dedupeBy DedupeBy, records []map[string]interface{}) ([]SyncResult, error) {
+	input := map[string]interface{}{
+		"action": action,
+		"input":  records,
+	}
+	if dedupeBy != "" {
+		input["dedupeBy"] = dedupeBy
 	}
 
-	raw := []map[string]interface{}{}
-	err = json.Unmarshal(response.Result, &raw)
+	results := []SyncResult{}
+	err := c.object(name).sync(ctx, syncCustomObjects, input, &results)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	results := make([]CustomObjectResult, len(raw))
-	for i, l := range raw {
-		err = mapstructure.Decode(l, &results[i])
+	return results, nil
+}
+
+// Delete deletes the custom object records with the provided Marketo GUIDs,
+// returning the result for each. At most MaximumQueryBatchSize records may be
+// deleted in a single call.
+func (c *CustomObjects) Delete(ctx context.Context, name string, guids []string) ([]DeleteResult, error) {
+	if len(guids) > MaximumQueryBatchSize {
+		return nil, fmt.Errorf("too many records: %d exceeds the maximum of %d",
+			len(guids), MaximumQueryBatchSize)
+	}
+
+	type record struct {
+		MarketoGUID string `json:"marketoGUID"`
+	}
+	input := struct {
+		DeleteBy DedupeBy `json:"deleteBy"`
+		Input    []record `json:"input"`
+	}{DeleteBy: DedupeByIDField}
+	for _, guid := range guids {
+		input.Input = append(input.Input, record{guid})
+	}
+
+	results := []DeleteResult{}
+	err := c.object(name).delete(ctx, deleteCustomObjects, input, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DeleteByFilter deletes the custom object records whose filterField matches
+// any of values, returning the result for each record deleted. The matching
+// records are found with FilterAll, and deleted in batches of
+// MaximumQueryBatchSize.
+func (c *CustomObjects) DeleteByFilter(ctx context.Context, name, filterField string, values []string) ([]DeleteResult, error) {
+	records, err := c.FilterAll(ctx, name,
+		FilterField(filterField), FilterValues(values), GetFields("marketoGUID"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []DeleteResult{}
+	for start := 0; start < len(records); start += MaximumQueryBatchSize {
+		end := start + MaximumQueryBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		guids := make([]string, 0, end-start)
+		for _, record := range records[start:end] {
+			guids = append(guids, record.MarketoGUID)
+		}
+		deleted, err := c.Delete(ctx, name, guids)
 		if err != nil {
-			return nil, "", err
+			return results, err
 		}
+		results = append(results, deleted...)
 	}
-
-	return results, response.NextPageToken, nil
+	return results, nil
 }
@@ -2,6 +2,8 @@ package marketo
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -44,6 +46,77 @@ func TestListCustomObjects(t *testing.T) {
 	assert.True(t, gock.IsDone())
 }
 
+func TestListCustomObjectsPaged(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects.json").
+		MatchParam("nextPageToken", "PAGE2").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"name":"second_c"}]}`)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"PAGE2","result":[{"name":"first_c"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	objects, err := NewCustomObjectsAPI(client).List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "first_c", objects[0].APIName)
+	assert.Equal(t, "second_c", objects[1].APIName)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectDescribeAll(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"name":"first_c"},{"name":"second_c"}]}`)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects/first_c/describe.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"name":"first_c","displayName":"First"}]}`)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects/second_c/describe.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"name":"second_c","displayName":"Second"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	objects, err := NewCustomObjectsAPI(client).DescribeAll(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "First", objects["first_c"].DisplayName)
+	assert.Equal(t, "Second", objects["second_c"].DisplayName)
+
+	assert.True(t, gock.IsDone())
+}
+
 func TestCustomObjectDescribe(t *testing.T) {
 	t.Run("custom object", func(t *testing.T) {
 		defer gock.Off()
@@ -151,3 +224,726 @@ func TestFitlerCustomObjects(t *testing.T) {
 	assert.Equal(t, "nathan@polytomic.com", leads[0].Fields["email"])
 	assert.True(t, gock.IsDone())
 }
+
+func TestCustomObjectDescribeBoth(t *testing.T) {
+	t.Run("approved", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/v1/customobjects/schema/testObject_c/describe.json").
+			Reply(http.StatusOK).
+			JSON(`{"success":true,"result":[{"name":"testObject_c","state":"approved","version":"approved"}]}`)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewCustomObjectsAPI(client)
+		draft, approved, err := api.DescribeBoth(context.Background(), "testObject_c")
+		require.NoError(t, err)
+		assert.Nil(t, draft)
+		require.NotNil(t, approved)
+		assert.Equal(t, ApprovedVersion, approved.Version)
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("approved with draft", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/v1/customobjects/schema/testObject_c/describe.json").
+			MatchParam("state", "draft").
+			Reply(http.StatusOK).
+			JSON(`{"success":true,"result":[{"name":"testObject_c","state":"approvedWithDraft","version":"draft","displayName":"Draft"}]}`)
+		gock.New(testHost).
+			Get("/rest/v1/customobjects/schema/testObject_c/describe.json").
+			Reply(http.StatusOK).
+			JSON(`{"success":true,"result":[{"name":"testObject_c","state":"approvedWithDraft","version":"approved","displayName":"Approved"}]}`)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewCustomObjectsAPI(client)
+		draft, approved, err := api.DescribeBoth(context.Background(), "testObject_c")
+		require.NoError(t, err)
+		require.NotNil(t, draft)
+		require.NotNil(t, approved)
+		assert.Equal(t, "Draft", draft.DisplayName)
+		assert.Equal(t, "Approved", approved.DisplayName)
+
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestCustomObjectSchemaActions(t *testing.T) {
+	t.Run("delete field", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Post("/rest/v1/customobjects/schema/testObject_c/deleteField.json").
+			JSON(map[string]interface{}{
+				"input": []map[string]string{{"name": "firstName"}},
+			}).
+			Reply(http.StatusOK).
+			JSON(`{"success":true,"result":[{"name":"testObject_c","status":"deleted"}]}`)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewCustomObjectsAPI(client)
+		assert.NoError(t, api.DeleteField(context.Background(), "testObject_c", "firstName"))
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("delete object", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Post("/rest/v1/customobjects/schema/testObject_c/delete.json").
+			Reply(http.StatusOK).
+			JSON(`{"success":true,"result":[{"name":"testObject_c","status":"deleted"}]}`)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewCustomObjectsAPI(client)
+		assert.NoError(t, api.DeleteObject(context.Background(), "testObject_c"))
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("discard error", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Post("/rest/v1/customobjects/schema/testObject_c/discardDraft.json").
+			Reply(http.StatusOK).
+			JSON(`{"success":false,"errors":[{"code":"1003","message":"No draft version found"}]}`)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewCustomObjectsAPI(client)
+		err = api.Discard(context.Background(), "testObject_c")
+		assert.EqualError(t, err, "No draft version found")
+
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestCustomObjectDescribeNotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects/unknown/describe.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	_, err = api.Describe(context.Background(), "unknown")
+	assert.True(t, errors.Is(err, ErrObjectNotFound))
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectMetadataRoundTrip(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects/testObject_c/describe.json").
+		Reply(http.StatusOK).
+		File("test-fixtures/testObject_c-describe.json")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	obj, err := api.Describe(context.Background(), "testObject_c")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	decoded := &CustomObjectMetadata{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, obj, decoded)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestObjectFieldSearchableOmitted(t *testing.T) {
+	data, err := json.Marshal(ObjectField{Name: "email", DataType: "email"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "searchable")
+
+	data, err = json.Marshal(ObjectField{Name: "email", DataType: "email", Searchable: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"searchable":true`)
+}
+
+func TestSyncCustomObjects(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		JSON(map[string]interface{}{
+			"action":   "createOnly",
+			"dedupeBy": "dedupeFields",
+			"input": []map[string]interface{}{
+				{"email": "nathan@polytomic.com"},
+				{"email": "ghalib@polytomic.com"},
+			},
+		}).
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"result": [
+				{"seq": 0, "marketoGUID": "dff23271-f996-47d7-984f-f2676861b5fa", "status": "created"},
+				{"seq": 1, "status": "skipped", "reasons": [{"code": "1017", "message": "Object already exists"}]}
+			]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	results, err := api.Sync(context.Background(), "testObject_c",
+		SyncCreateOnly, DedupeByDedupeFields,
+		[]map[string]interface{}{
+			{"email": "nathan@polytomic.com"},
+			{"email": "ghalib@polytomic.com"},
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.False(t, results[0].IsDuplicate())
+	assert.Equal(t, "skipped", results[1].Status)
+	assert.True(t, results[1].IsDuplicate())
+	assert.True(t, results[1].HasReason(ErrObjectAlreadyExists))
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectGetByKey(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects/testObject_c/describe.json").
+		Reply(http.StatusOK).
+		File("test-fixtures/testObject_c-describe.json")
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			return r.PostForm.Get("nextPageToken") == "", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"PAGE2","result":[{"seq":0,"marketoGUID":"a","email":"nathan@polytomic.com"}]}`)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "email", r.PostForm.Get("filterType"))
+			assert.Equal(t, "email,firstName", r.PostForm.Get("fields"))
+			return r.PostForm.Get("nextPageToken") == "PAGE2", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":1,"marketoGUID":"b","email":"ghalib@polytomic.com"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	results, err := api.GetByKey(context.Background(), "testObject_c", "email",
+		[]string{"nathan@polytomic.com", "ghalib@polytomic.com"},
+		[]string{"email", "firstName"},
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "b", results[1].MarketoGUID)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectFilterNumbers(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"marketoGUID":"a","accountNumber":123456789012345678,"amount":19.99}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	results, _, err := api.Filter(context.Background(), "testObject_c",
+		FilterField("marketoGUID"),
+		FilterValues([]string{"a"}),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, json.Number("123456789012345678"), results[0].Fields["accountNumber"])
+	assert.Equal(t, json.Number("19.99"), results[0].Fields["amount"])
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectGetByGUID(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "marketoGUID", r.PostForm.Get("filterType"))
+			assert.Equal(t, "a,b", r.PostForm.Get("filterValues"))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"marketoGUID":"a"},{"seq":1,"marketoGUID":"b"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	results, err := api.GetByGUID(context.Background(), "testObject_c", []string{"a", "b"}, nil)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectFilterOne(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		Reply(http.StatusOK).
+		File("test-fixtures/filterCustomObject.json")
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[]}`)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"marketoGUID":"a"},{"seq":1,"marketoGUID":"b"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	opts := []QueryOption{
+		FilterField("email"),
+		FilterValues([]string{"nathan@polytomic.com"}),
+	}
+	result, err := api.FilterOne(context.Background(), "testObject_c", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, "55ac5221-e29b-40ce-8bc8-fea5bc6069ef", result.MarketoGUID)
+
+	_, err = api.FilterOne(context.Background(), "testObject_c", opts...)
+	assert.True(t, errors.Is(err, ErrObjectNotFound))
+
+	_, err = api.FilterOne(context.Background(), "testObject_c", opts...)
+	assert.True(t, errors.Is(err, ErrMultipleObjects))
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectFilterAllMaxRecords(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "2", r.PostForm.Get("batchSize"))
+			return r.PostForm.Get("nextPageToken") == "", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"PAGE2","result":[{"seq":0,"marketoGUID":"a"},{"seq":1,"marketoGUID":"b"}]}`)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "1", r.PostForm.Get("batchSize"))
+			assert.Empty(t, r.PostForm.Get("maxRecords"))
+			return r.PostForm.Get("nextPageToken") == "PAGE2", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"PAGE3","result":[{"seq":0,"marketoGUID":"c"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	results, err := api.FilterAll(context.Background(), "testObject_c",
+		FilterField("marketoGUID"),
+		FilterValues([]string{"a", "b", "c", "d"}),
+		BatchSize(2),
+		WithMaxRecords(3),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "c", results[2].MarketoGUID)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectParent(t *testing.T) {
+	object := CustomObjectMetadata{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "car_c",
+		"relationships": [
+			{"field": "email", "relatedTo": {"name": "Lead", "field": "email"}, "type": "Child"},
+			{"field": "dealer", "relatedTo": {"name": "dealer_c", "field": "id"}, "type": "sibling"}
+		]
+	}`), &object))
+
+	assert.Equal(t, RelationChild, object.Relationships[0].Type)
+	assert.True(t, object.Relationships[0].Type.Known())
+	assert.Equal(t, RelationType("sibling"), object.Relationships[1].Type)
+	assert.False(t, object.Relationships[1].Type.Known())
+
+	parent, ok := object.Parent()
+	require.True(t, ok)
+	assert.Equal(t, "Lead", parent.RelatedTo.Name)
+
+	_, ok = CustomObjectMetadata{}.Parent()
+	assert.False(t, ok)
+}
+
+func TestCustomObjectDeleteByFilter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "email", r.PostForm.Get("filterType"))
+			assert.Equal(t, "marketoGUID", r.PostForm.Get("fields"))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"marketoGUID":"a"},{"seq":1,"marketoGUID":"b"}]}`)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c/delete.json").
+		JSON(map[string]interface{}{
+			"deleteBy": "idField",
+			"input": []map[string]string{
+				{"marketoGUID": "a"},
+				{"marketoGUID": "b"},
+			},
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"marketoGUID":"a","status":"deleted"},{"seq":1,"status":"skipped","reasons":[{"code":"1013","message":"Record not found"}]}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	results, err := api.DeleteByFilter(context.Background(), "testObject_c", "email",
+		[]string{"nathan@polytomic.com", "ghalib@polytomic.com"},
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "deleted", results[0].Status)
+	assert.Equal(t, "skipped", results[1].Status)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestConditionalDescribe(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/customobjects/testObject_c/describe.json").
+		Reply(http.StatusOK).
+		SetHeader("ETag", `"v1"`).
+		File("test-fixtures/testObject_c-describe.json")
+	gock.New(testHost).
+		Get("/rest/v1/customobjects/testObject_c/describe.json").
+		MatchHeader("If-None-Match", `"v1"`).
+		Reply(http.StatusNotModified)
+
+	client, err := NewClient(ClientConfig{
+		ID:                  clientID,
+		Secret:              clientSecret,
+		Endpoint:            "https://marketo.testing",
+		ConditionalRequests: true,
+	})
+	require.NoError(t, err)
+
+	api := NewCustomObjectsAPI(client)
+	first, err := api.Describe(context.Background(), "testObject_c")
+	require.NoError(t, err)
+	second, err := api.Describe(context.Background(), "testObject_c")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestFilterEscapesName(t *testing.T) {
+	cases := map[string]string{
+		"testObject_c":  "/rest/v1/customobjects/testObject_c.json",
+		"test_object_c": "/rest/v1/customobjects/test_object_c.json",
+		"test-object-c": "/rest/v1/customobjects/test-object-c.json",
+		"test.object_c": "/rest/v1/customobjects/test.object_c.json",
+		"test/object?c": "/rest/v1/customobjects/test%2Fobject%3Fc.json",
+	}
+	for name, path := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New(testHost).
+				Get("/identity/oauth/token").
+				Reply(http.StatusOK).
+				JSON(authResponseSuccess)
+			gock.New(testHost).
+				Post("/rest/v1/customobjects/").
+				AddMatcher(func(r *http.Request, _ *gock.Request) (bool, error) {
+					return r.URL.EscapedPath() == path && r.URL.RawQuery == "_method=GET", nil
+				}).
+				Reply(http.StatusOK).
+				File("test-fixtures/filterCustomObject.json")
+
+			client, err := NewClient(ClientConfig{
+				ID:       clientID,
+				Secret:   clientSecret,
+				Endpoint: "https://marketo.testing",
+			})
+			require.NoError(t, err)
+
+			_, _, err = NewCustomObjectsAPI(client).Filter(context.Background(), name,
+				FilterField("email"),
+				FilterValues([]string{"nathan@polytomic.com"}),
+			)
+			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
+
+func TestCustomObjectCount(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, _ *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "marketoGUID", r.PostForm.Get("fields"))
+			assert.Equal(t, "300", r.PostForm.Get("batchSize"))
+			return r.PostForm.Get("nextPageToken") == "", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"nextPageToken":"page2","result":[{"seq":0,"marketoGUID":"a"},{"seq":1,"marketoGUID":"b"}]}`)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(func(r *http.Request, _ *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			return r.PostForm.Get("nextPageToken") == "page2", nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"marketoGUID":"c"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+	})
+	require.NoError(t, err)
+
+	count, err := NewCustomObjectsAPI(client).Count(context.Background(), "testObject_c",
+		FilterField("email"),
+		FilterValues([]string{"nathan@polytomic.com"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.True(t, gock.IsDone())
+}
+
+func TestCustomObjectPagingRequiresFilter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+	})
+	require.NoError(t, err)
+	api := NewCustomObjectsAPI(client)
+
+	_, err = api.Count(context.Background(), "testObject_c")
+	assert.EqualError(t, err, "no filter field")
+	_, err = api.FilterAll(context.Background(), "testObject_c", FilterField("email"))
+	assert.EqualError(t, err, "too few values")
+
+	// no filter requests are made
+	assert.True(t, gock.IsDone())
+}
+
+func TestMarkSearchable(t *testing.T) {
+	fields := markSearchable([]ObjectField{
+		{Name: "email"},
+		{Name: "firstName", Searchable: true},
+		{Name: "accountId"},
+		{Name: "productId"},
+	}, [][]string{{"email"}, {"accountId", "productId"}})
+
+	searchable := map[string]bool{}
+	for _, f := range fields {
+		searchable[f.Name] = f.Searchable
+	}
+	assert.Equal(t, map[string]bool{
+		"email":     true,
+		"firstName": false,
+		"accountId": true,
+		"productId": true,
+	}, searchable)
+}
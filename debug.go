@@ -0,0 +1,118 @@
+package marketo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultDebugBodyLimit is the number of bytes of each request and
+	// response body written when debugging bodies
+	DefaultDebugBodyLimit = 4096
+	redacted              = "[REDACTED]"
+)
+
+// debugRoundTripper writes request and response bodies to a writer, with
+// the bearer token redacted.
+type debugRoundTripper struct {
+	delegate http.RoundTripper
+	w        io.Writer
+	limit    int
+	lock     sync.Mutex
+}
+
+func (rt *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	delegate := rt.delegate
+	if delegate == nil {
+		delegate = http.DefaultTransport
+	}
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	prefix := logPrefix(req.Context())
+	rt.write(token, fmt.Sprintf("%s> %s %s", prefix, req.Method, req.URL), body, len(body))
+
+	resp, err := delegate.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &debugBody{
+		ReadCloser: resp.Body,
+		rt:         rt,
+		token:      token,
+		header:     fmt.Sprintf("%s< %s %s", prefix, resp.Status, req.URL),
+	}
+	return resp, nil
+}
+
+// write writes a single request or response to the debug writer, truncating
+// the body to the configured limit.
+func (rt *debugRoundTripper) write(token, header string, body []byte, size int) {
+	out := &bytes.Buffer{}
+	out.WriteString(header)
+	out.WriteString("\n")
+	if len(body) > rt.limit {
+		body = body[:rt.limit]
+	}
+	out.Write(body)
+	if size > len(body) {
+		fmt.Fprintf(out, "\n... (truncated, %d bytes total)", size)
+	}
+	out.WriteString("\n")
+
+	result := out.Bytes()
+	if token != "" {
+		result = bytes.ReplaceAll(result, []byte(token), []byte(redacted))
+	}
+
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	rt.w.Write(result)
+}
+
+// debugBody captures the beginning of a response body as it is read, writing
+// it to the debug writer when closed. The body is not buffered beyond the
+// configured limit, so streaming responses are not held in memory.
+type debugBody struct {
+	io.ReadCloser
+	rt     *debugRoundTripper
+	token  string
+	header string
+	buf    []byte
+	size   int
+	closed bool
+}
+
+func (b *debugBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if remaining := b.rt.limit - len(b.buf); remaining > 0 {
+		if remaining > n {
+			remaining = n
+		}
+		b.buf = append(b.buf, p[:remaining]...)
+	}
+	b.size += n
+	return n, err
+}
+
+func (b *debugBody) Close() error {
+	if !b.closed {
+		b.closed = true
+		b.rt.write(b.token, b.header, b.buf, b.size)
+	}
+	return b.ReadCloser.Close()
+}
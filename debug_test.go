@@ -0,0 +1,54 @@
+package marketo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestDebugBodies(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":1,"status":"Queued"}]}`)
+
+	out := &strings.Builder{}
+	client, err := NewClient(ClientConfig{
+		ID:             clientID,
+		Secret:         clientSecret,
+		Endpoint:       "https://marketo.testing",
+		DebugBodies:    out,
+		DebugBodyLimit: 256,
+	})
+	require.NoError(t, err)
+	// the token is echoed in the body to verify it is redacted
+	client.restRoundTripper.setToken("secret-token")
+
+	api := NewImportAPI(client)
+	ctx := WithCorrelationID(context.Background(), "sync-42")
+	_, err = api.Create(ctx, Leads, strings.NewReader(
+		"email\nsecret-token@polytomic.com\n"+strings.Repeat("nathan@polytomic.com\n", 100),
+	))
+	require.NoError(t, err)
+
+	debug := out.String()
+	assert.Contains(t, debug, "[sync-42] > POST https://marketo.testing/bulk/v1/leads.json")
+	assert.Contains(t, debug, "[sync-42] < 200")
+	assert.Contains(t, debug, "truncated")
+	assert.Contains(t, debug, `"batchId":1`)
+	assert.NotContains(t, debug, "secret-token")
+	assert.Contains(t, debug, redacted)
+
+	assert.True(t, gock.IsDone())
+}
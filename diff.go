@@ -0,0 +1,145 @@
+package marketo
+
+import "sort"
+
+// FieldChange describes a field present in both schemas whose definition
+// differs.
+type FieldChange struct {
+	Name   string
+	Before ObjectField
+	After  ObjectField
+}
+
+// RelationChange describes a relationship present in both schemas whose
+// definition differs.
+type RelationChange struct {
+	Field  string
+	Before ObjectRelation
+	After  ObjectRelation
+}
+
+// SchemaDiff contains the differences between two custom object schemas.
+type SchemaDiff struct {
+	AddedFields   []ObjectField
+	RemovedFields []ObjectField
+	ChangedFields []FieldChange
+
+	AddedDedupeFields   []string
+	RemovedDedupeFields []string
+
+	AddedRelationships   []ObjectRelation
+	RemovedRelationships []ObjectRelation
+	ChangedRelationships []RelationChange
+}
+
+// Empty returns true if the schemas compared had no differences.
+func (d SchemaDiff) Empty() bool {
+	return len(d.AddedFields) == 0 &&
+		len(d.RemovedFields) == 0 &&
+		len(d.ChangedFields) == 0 &&
+		len(d.AddedDedupeFields) == 0 &&
+		len(d.RemovedDedupeFields) == 0 &&
+		len(d.AddedRelationships) == 0 &&
+		len(d.RemovedRelationships) == 0 &&
+		len(d.ChangedRelationships) == 0
+}
+
+// Diff compares two custom object schemas, reporting the changes required to
+// go from a to b. Fields and relationships are matched by name, so
+// reordering is not reported as a change. Results are sorted by name.
+func Diff(a, b CustomObjectMetadata) SchemaDiff {
+	diff := SchemaDiff{}
+
+	before := map[string]ObjectField{}
+	for _, f := range a.Fields {
+		before[f.Name] = f
+	}
+	after := map[string]ObjectField{}
+	for _, f := range b.Fields {
+		after[f.Name] = f
+		prev, ok := before[f.Name]
+		if !ok {
+			diff.AddedFields = append(diff.AddedFields, f)
+			continue
+		}
+		if prev != f {
+			diff.ChangedFields = append(diff.ChangedFields, FieldChange{
+				Name:   f.Name,
+				Before: prev,
+				After:  f,
+			})
+		}
+	}
+	for _, f := range a.Fields {
+		if _, ok := after[f.Name]; !ok {
+			diff.RemovedFields = append(diff.RemovedFields, f)
+		}
+	}
+
+	diff.AddedDedupeFields = missing(b.DedupeFields, a.DedupeFields)
+	diff.RemovedDedupeFields = missing(a.DedupeFields, b.DedupeFields)
+
+	beforeRel := map[string]ObjectRelation{}
+	for _, r := range a.Relationships {
+		beforeRel[r.Field] = r
+	}
+	afterRel := map[string]ObjectRelation{}
+	for _, r := range b.Relationships {
+		afterRel[r.Field] = r
+		prev, ok := beforeRel[r.Field]
+		if !ok {
+			diff.AddedRelationships = append(diff.AddedRelationships, r)
+			continue
+		}
+		if prev != r {
+			diff.ChangedRelationships = append(diff.ChangedRelationships, RelationChange{
+				Field:  r.Field,
+				Before: prev,
+				After:  r,
+			})
+		}
+	}
+	for _, r := range a.Relationships {
+		if _, ok := afterRel[r.Field]; !ok {
+			diff.RemovedRelationships = append(diff.RemovedRelationships, r)
+		}
+	}
+
+	sort.Slice(diff.AddedFields, func(i, j int) bool {
+		return diff.AddedFields[i].Name < diff.AddedFields[j].Name
+	})
+	sort.Slice(diff.RemovedFields, func(i, j int) bool {
+		return diff.RemovedFields[i].Name < diff.RemovedFields[j].Name
+	})
+	sort.Slice(diff.ChangedFields, func(i, j int) bool {
+		return diff.ChangedFields[i].Name < diff.ChangedFields[j].Name
+	})
+	sort.Slice(diff.AddedRelationships, func(i, j int) bool {
+		return diff.AddedRelationships[i].Field < diff.AddedRelationships[j].Field
+	})
+	sort.Slice(diff.RemovedRelationships, func(i, j int) bool {
+		return diff.RemovedRelationships[i].Field < diff.RemovedRelationships[j].Field
+	})
+	sort.Slice(diff.ChangedRelationships, func(i, j int) bool {
+		return diff.ChangedRelationships[i].Field < diff.ChangedRelationships[j].Field
+	})
+
+	return diff
+}
+
+// missing returns the sorted values in a which are not present in b
+func missing(a, b []string) []string {
+	present := map[string]bool{}
+	for _, v := range b {
+		present[v] = true
+	}
+
+	var result []string
+	for _, v := range a {
+		if !present[v] {
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
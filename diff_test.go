@@ -0,0 +1,65 @@
+package marketo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	a := CustomObjectMetadata{
+		APIName:      "testObject_c",
+		DedupeFields: []string{"email"},
+		Fields: []ObjectField{
+			{Name: "email", DataType: "email", Length: 255},
+			{Name: "firstName", DataType: "string", Length: 255},
+			{Name: "score", DataType: "integer"},
+		},
+		Relationships: []ObjectRelation{
+			{Field: "leadId", RelatedTo: RelatedObject{Field: "id", Name: "Lead"}, Type: "child"},
+		},
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		assert.True(t, Diff(a, a).Empty())
+	})
+
+	t.Run("reordered", func(t *testing.T) {
+		b := a
+		b.Fields = []ObjectField{a.Fields[2], a.Fields[0], a.Fields[1]}
+		assert.True(t, Diff(a, b).Empty())
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		b := a
+		b.DedupeFields = []string{"externalId"}
+		b.Fields = []ObjectField{
+			{Name: "email", DataType: "email", Length: 255},
+			{Name: "score", DataType: "float"},
+			{Name: "externalId", DataType: "string", Length: 36},
+		}
+		b.Relationships = []ObjectRelation{
+			{Field: "leadId", RelatedTo: RelatedObject{Field: "email", Name: "Lead"}, Type: "child"},
+		}
+
+		diff := Diff(a, b)
+		assert.False(t, diff.Empty())
+
+		require.Len(t, diff.AddedFields, 1)
+		assert.Equal(t, "externalId", diff.AddedFields[0].Name)
+		require.Len(t, diff.RemovedFields, 1)
+		assert.Equal(t, "firstName", diff.RemovedFields[0].Name)
+		require.Len(t, diff.ChangedFields, 1)
+		assert.Equal(t, "integer", diff.ChangedFields[0].Before.DataType)
+		assert.Equal(t, "float", diff.ChangedFields[0].After.DataType)
+
+		assert.Equal(t, []string{"externalId"}, diff.AddedDedupeFields)
+		assert.Equal(t, []string{"email"}, diff.RemovedDedupeFields)
+
+		assert.Empty(t, diff.AddedRelationships)
+		assert.Empty(t, diff.RemovedRelationships)
+		require.Len(t, diff.ChangedRelationships, 1)
+		assert.Equal(t, "email", diff.ChangedRelationships[0].After.RelatedTo.Field)
+	})
+}
@@ -0,0 +1,55 @@
+package marketo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// munchkinID matches a Marketo instance ID, such as 123-ABC-456
+var munchkinID = regexp.MustCompile(`^[0-9]{3}-[A-Za-z]{3}-[0-9]{3}$`)
+
+// RESTEndpoint returns the REST API endpoint for the provided Munchkin ID or
+// instance URL, suitable for ClientConfig.Endpoint. A Munchkin ID such as
+// 123-ABC-456 is expanded to https://123-ABC-456.mktorest.com; URLs are
+// reduced to their scheme and host, so the REST or identity URLs copied from
+// Admin > Web Services are accepted as-is. An error describing the problem is
+// returned for the Marketo application URL, and for other malformed input.
+func RESTEndpoint(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", errors.New("empty Marketo endpoint")
+	}
+	if munchkinID.MatchString(s) {
+		return fmt.Sprintf("https://%s.mktorest.com", s), nil
+	}
+
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid Marketo endpoint %q: %w", s, err)
+	}
+	if u.Scheme != "https" {
+		return "", fmt.Errorf("invalid Marketo endpoint %q: the REST API requires https", s)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", fmt.Errorf("invalid Marketo endpoint %q: no host", s)
+	}
+	if host == "marketo.com" || strings.HasSuffix(host, ".marketo.com") {
+		return "", fmt.Errorf(
+			"invalid Marketo endpoint %q: this is the Marketo application URL; "+
+				"use the REST API endpoint from Admin > Web Services, such as https://123-ABC-456.mktorest.com", s)
+	}
+	if strings.HasSuffix(host, ".mktorest.com") &&
+		!munchkinID.MatchString(strings.TrimSuffix(host, ".mktorest.com")) {
+		return "", fmt.Errorf("invalid Marketo endpoint %q: %s is not a valid Munchkin ID", s,
+			strings.TrimSuffix(u.Hostname(), ".mktorest.com"))
+	}
+
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host), nil
+}
@@ -0,0 +1,42 @@
+package marketo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTEndpoint(t *testing.T) {
+	valid := map[string]string{
+		"123-ABC-456":                               "https://123-ABC-456.mktorest.com",
+		" 123-ABC-456 ":                             "https://123-ABC-456.mktorest.com",
+		"123-ABC-456.mktorest.com":                  "https://123-ABC-456.mktorest.com",
+		"https://123-ABC-456.mktorest.com/":         "https://123-ABC-456.mktorest.com",
+		"https://123-ABC-456.mktorest.com/rest":     "https://123-ABC-456.mktorest.com",
+		"https://123-ABC-456.mktorest.com/rest/v1":  "https://123-ABC-456.mktorest.com",
+		"https://123-ABC-456.mktorest.com/identity": "https://123-ABC-456.mktorest.com",
+		"https://marketo.proxy.example:8443/":       "https://marketo.proxy.example:8443",
+	}
+	for input, expected := range valid {
+		t.Run(input, func(t *testing.T) {
+			endpoint, err := RESTEndpoint(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, endpoint)
+		})
+	}
+
+	invalid := map[string]string{
+		"":                                "empty",
+		"https://app-ab12.marketo.com/":   "application URL",
+		"http://123-ABC-456.mktorest.com": "https",
+		"https://123-ABC.mktorest.com":    "Munchkin ID",
+	}
+	for input, message := range invalid {
+		t.Run(input, func(t *testing.T) {
+			_, err := RESTEndpoint(input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), message)
+		})
+	}
+}
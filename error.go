@@ -3,6 +3,7 @@ package marketo
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -20,6 +21,19 @@ func (r Reason) Error() string {
 	return fmt.Sprintf("%s: %s", r.Code, r.Message)
 }
 
+// UnmarshalJSON decodes a Reason, accepting either an object with a code
+// and message or, as some endpoints return for warnings, a bare message.
+func (r *Reason) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*r = Reason{Message: message}
+		return nil
+	}
+
+	type reason Reason
+	return json.Unmarshal(data, (*reason)(r))
+}
+
 var (
 	ErrBadGateway                    = Reason{Code: "502"}
 	ErrEmptyAccessToken              = Reason{Code: "600"}
@@ -51,7 +65,10 @@ var (
 	ErrUnableToFindDefaultRecordType = Reason{Code: "714"}
 	ErrExternalSalesPersonIDNotFound = Reason{Code: "718"}
 
-	ErrTooManyImports = Reason{Code: "1016"}
+	ErrLeadAlreadyExists      = Reason{Code: "1005"}
+	ErrTooManyImports         = Reason{Code: "1016"}
+	ErrObjectAlreadyExists    = Reason{Code: "1017"}
+	ErrDuplicateObjectInInput = Reason{Code: "1036"}
 )
 
 // Error contains the error state returned from a Marketo operation
@@ -59,6 +76,14 @@ type Error struct {
 	Message    string
 	StatusCode int
 	Body       string
+	RequestID  string
+	// Attempts is the number of requests made before giving up; StatusCode
+	// is the HTTP status of the final attempt
+	Attempts int
+	// NotReplayable is true if the failure would have been retried, but
+	// the request body exceeded MaximumReplayBodySize and could not be
+	// sent again
+	NotReplayable bool
 
 	Errors []Reason
 }
@@ -86,6 +111,37 @@ func (e Error) Is(target error) bool {
 	return false
 }
 
+// HasCode returns true if any of the Reasons included with this Error has
+// the provided code, such as "1003".
+func (e Error) HasCode(code string) bool {
+	for _, r := range e.Errors {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Codes returns the codes of the Reasons included with this Error, in the
+// order Marketo returned them.
+func (e Error) Codes() []string {
+	codes := make([]string, len(e.Errors))
+	for i, r := range e.Errors {
+		codes[i] = r.Code
+	}
+	return codes
+}
+
+// ReasonCodes returns the codes of the Reasons included with err, if it is
+// or wraps an Error.
+func ReasonCodes(err error) []string {
+	var e Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	return e.Codes()
+}
+
 // Error fulfills the error interface
 func (e Error) Error() string {
 	if e.Message != "" {
@@ -99,11 +155,137 @@ func (e Error) Error() string {
 	return strings.Join(msgs, "; ")
 }
 
+// IsRateLimited returns true if err was caused by exceeding Marketo's rate or
+// concurrency limits, or an HTTP 429 response.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimitExceeded) ||
+		errors.Is(err, ErrConcurrentLimitReached) ||
+		hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsTokenExpired returns true if err was caused by an expired or invalid
+// access token.
+func IsTokenExpired(err error) bool {
+	return errors.Is(err, ErrAccessTokenExpired) || errors.Is(err, ErrAccessTokenInvalid)
+}
+
+// IsNotFound returns true if err indicates the requested resource or record
+// does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrObjectNotFound) ||
+		errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrNoDataFound) ||
+		hasStatus(err, http.StatusNotFound)
+}
+
+// IsQuotaExceeded returns true if err was caused by reaching the daily API
+// quota, which resets at midnight US Central time; unlike rate limits,
+// retrying before then will not succeed.
+func IsQuotaExceeded(err error) bool {
+	return errors.Is(err, ErrDailyQuotaReached)
+}
+
+// notFoundError is returned when Marketo responds successfully but without
+// the requested record, such as an import batch or export job; it wraps
+// ErrNotFound so IsNotFound and errors.Is recognize it.
+type notFoundError struct {
+	what string
+}
+
+func (e notFoundError) Error() string {
+	return e.what + " not found"
+}
+
+func (e notFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+// hasStatus returns true if err is an Error with the provided HTTP status
+func hasStatus(err error, status int) bool {
+	var e Error
+	return errors.As(err, &e) && e.StatusCode == status
+}
+
+// responseError returns an error if the response envelope indicates the
+// operation was unsuccessful. Marketo occasionally reports failure without
+// including any errors; the request ID is included so the failure can be
+// traced with Marketo support.
+func responseError(resp *http.Response, response *Response) error {
+	if len(response.Errors) > 0 {
+		err := ErrorForReasons(resp.StatusCode, response.Errors...)
+		err.RequestID = response.RequestID
+		err.Attempts = attempts(resp)
+		err.NotReplayable = notReplayable(resp)
+		return err
+	}
+	if !response.Success {
+		err := Error{
+			Message:    fmt.Sprintf("unsuccessful response without errors (request ID %s)", response.RequestID),
+			StatusCode: resp.StatusCode,
+			RequestID:  response.RequestID,
+		}
+		err.Attempts = attempts(resp)
+		err.NotReplayable = notReplayable(resp)
+		return err
+	}
+	return nil
+}
+
+// attempts returns the number of attempts made to receive resp
+func attempts(resp *http.Response) int {
+	if body, ok := resp.Body.(*responseBody); ok {
+		return body.attempts
+	}
+	return 1
+}
+
+// notReplayable returns true if resp would have been retried but for the
+// size of the request body
+func notReplayable(resp *http.Response) bool {
+	if body, ok := resp.Body.(*responseBody); ok {
+		return body.notReplayable
+	}
+	return false
+}
+
+// RequestError is returned when a request fails without receiving a
+// response, such as when the connection is refused or times out, after
+// any retries
+type RequestError struct {
+	// Attempts is the number of requests made before giving up
+	Attempts int
+	// NotReplayable is true if the request would have been retried, but its
+	// body exceeded MaximumReplayBodySize and could not be sent again
+	NotReplayable bool
+	Err           error
+}
+
+// Error fulfills the error interface
+func (e *RequestError) Error() string {
+	switch {
+	case e.NotReplayable:
+		return fmt.Sprintf("%v (not retried: request body exceeds %d bytes)", e.Err, MaximumReplayBodySize)
+	case e.Attempts > 1:
+		return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying transport error
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// maxErrorBodyBytes is the most read from the body of an unsuccessful
+// response, such as an HTML error page from a proxy; the remainder is
+// discarded
+const maxErrorBodyBytes = 64 << 10
+
 // handleError reads a non-successful HTTP responnse & returns an
 // error wrapping it; it is the callers responsibility to close
 // response.Body.
 func handleError(operation string, resp *http.Response) error {
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
 	if err != nil {
 		return errors.Wrap(err, "unable to read marketo error response")
 	}
@@ -112,12 +294,19 @@ func handleError(operation string, resp *http.Response) error {
 	response := Response{}
 	err = json.Unmarshal(body, &response)
 	if err == nil {
-		return ErrorForReasons(resp.StatusCode, response.Errors...)
+		e := ErrorForReasons(resp.StatusCode, response.Errors...)
+		e.RequestID = response.RequestID
+		e.Attempts = attempts(resp)
+		e.NotReplayable = notReplayable(resp)
+		return e
 	}
 
-	return Error{
+	e := Error{
 		Message:    fmt.Sprintf("error: %s", operation),
 		Body:       string(body),
 		StatusCode: resp.StatusCode,
 	}
+	e.Attempts = attempts(resp)
+	e.NotReplayable = notReplayable(resp)
+	return e
 }
@@ -0,0 +1,47 @@
+package marketo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorPredicates(t *testing.T) {
+	wrap := func(err error) error {
+		return fmt.Errorf("describe: %w", err)
+	}
+
+	rateLimited := wrap(ErrorForReasons(http.StatusOK, ErrRateLimitExceeded))
+	assert.True(t, IsRateLimited(rateLimited))
+	assert.True(t, IsRateLimited(ErrorForReasons(http.StatusTooManyRequests)))
+	assert.False(t, IsQuotaExceeded(rateLimited))
+
+	assert.True(t, IsTokenExpired(wrap(ErrorForReasons(http.StatusOK, ErrAccessTokenExpired))))
+	assert.True(t, IsQuotaExceeded(ErrorForReasons(http.StatusOK, ErrDailyQuotaReached)))
+
+	assert.True(t, IsNotFound(wrap(ErrObjectNotFound)))
+	assert.True(t, IsNotFound(ErrorForReasons(http.StatusOK, ErrNotFound)))
+	assert.True(t, IsNotFound(ErrorForReasons(http.StatusNotFound)))
+
+	other := errors.New("connection reset")
+	assert.False(t, IsRateLimited(other))
+	assert.False(t, IsTokenExpired(other))
+	assert.False(t, IsNotFound(other))
+	assert.False(t, IsQuotaExceeded(nil))
+}
+
+func TestErrorCodes(t *testing.T) {
+	err := ErrorForReasons(http.StatusOK,
+		Reason{Code: "1003", Message: "Invalid value"},
+		Reason{Code: "1006", Message: "Field not found"},
+	)
+	assert.True(t, err.HasCode("1006"))
+	assert.False(t, err.HasCode("606"))
+	assert.Equal(t, []string{"1003", "1006"}, err.Codes())
+
+	assert.Equal(t, []string{"1003", "1006"}, ReasonCodes(fmt.Errorf("sync: %w", err)))
+	assert.Nil(t, ReasonCodes(errors.New("connection reset")))
+}
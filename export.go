@@ -0,0 +1,398 @@
+package marketo
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportObject identifies the kind of records a bulk export retrieves
+type ExportObject struct {
+	path string
+}
+
+var (
+	LeadExports     = ExportObject{path: "leads/export"}
+	ActivityExports = ExportObject{path: "activities/export"}
+	exportObjects   = map[string]ExportObject{
+		"lead":     LeadExports,
+		"activity": ActivityExports,
+	}
+)
+
+// ExportObjectForAPIName returns the ExportObject given the API name of a
+// Marketo object.
+func ExportObjectForAPIName(apiName string) ExportObject {
+	if obj, ok := exportObjects[apiName]; ok {
+		return obj
+	}
+	return ExportObject{path: fmt.Sprintf("customobjects/%s/export", escapeName(apiName))}
+}
+
+// ExportFormat is the format of an export file
+type ExportFormat string
+
+const (
+	// ExportCSV is comma separated values, the default
+	ExportCSV ExportFormat = "CSV"
+	// ExportTSV is tab separated values
+	ExportTSV ExportFormat = "TSV"
+	// ExportSSV is space separated values
+	ExportSSV ExportFormat = "SSV"
+)
+
+// delimiter returns the character separating values in the format
+func (f ExportFormat) delimiter() rune {
+	switch f {
+	case ExportTSV:
+		return '\t'
+	case ExportSSV:
+		return ' '
+	}
+	return ','
+}
+
+// exportOptions contains the optional parameters for creating an export
+type exportOptions struct {
+	format      ExportFormat
+	headerNames map[string]string
+}
+
+// ExportOption defines the signature of functional options for Marketo
+// bulk export APIs.
+type ExportOption func(*exportOptions)
+
+// WithExportFormat sets the format of the export file; ExportCSV is used
+// when omitted.
+func WithExportFormat(format ExportFormat) ExportOption {
+	return func(o *exportOptions) {
+		o.format = format
+	}
+}
+
+// WithColumnHeaderNames sets the names used in the export file's header for
+// the provided fields, keyed by field API name. Records passed to FileStream
+// are keyed by these names.
+func WithColumnHeaderNames(names map[string]string) ExportOption {
+	return func(o *exportOptions) {
+		o.headerNames = names
+	}
+}
+
+// ExportStatus is the processing status of an export job
+type ExportStatus string
+
+const (
+	ExportCreated    ExportStatus = "Created"
+	ExportQueued     ExportStatus = "Queued"
+	ExportProcessing ExportStatus = "Processing"
+	ExportCancelled  ExportStatus = "Cancelled"
+	ExportCompleted  ExportStatus = "Completed"
+	ExportFailed     ExportStatus = "Failed"
+)
+
+// ExportJob contains the details of a bulk export job
+type ExportJob struct {
+	ExportID        string       `json:"exportId"`
+	Format          ExportFormat `json:"format"`
+	Status          ExportStatus `json:"status"`
+	CreatedAt       time.Time    `json:"createdAt"`
+	QueuedAt        *time.Time   `json:"queuedAt,omitempty"`
+	StartedAt       *time.Time   `json:"startedAt,omitempty"`
+	FinishedAt      *time.Time   `json:"finishedAt,omitempty"`
+	NumberOfRecords int          `json:"numberOfRecords,omitempty"`
+	FileSize        int64        `json:"fileSize,omitempty"`
+	FileChecksum    string       `json:"fileChecksum,omitempty"`
+	ErrorMessage    string       `json:"errorMsg,omitempty"`
+}
+
+const (
+	createExport  = "create bulk export"
+	enqueueExport = "enqueue bulk export"
+	getExport     = "get export status"
+	getExportFile = "get export file"
+	listExports   = "list exports"
+	cancelExport  = "cancel export"
+)
+
+// ExportAPI provides access to the Marketo bulk export API for a single kind
+// of record
+type ExportAPI struct {
+	c   *Client
+	obj ExportObject
+}
+
+// NewExportAPI returns a new instance of the export API for the provided
+// object, configured with the provided Client.
+func NewExportAPI(c *Client, obj ExportObject) *ExportAPI {
+	return &ExportAPI{c: c, obj: obj}
+}
+
+// url returns the URL of the export endpoint with the provided path
+func (e *ExportAPI) url(path string) string {
+	return e.c.url("bulk", "v1", e.obj.path+path)
+}
+
+// Create creates a new export job retrieving the provided fields of records
+// matching filter, for example a "createdAt" range with "startAt" and "endAt"
+// times; the filters supported vary by object. The job must be enqueued
+// before it is processed.
+func (e *ExportAPI) Create(ctx context.Context, fields []string, filter map[string]interface{}, opts ...ExportOption) (*ExportJob, error) {
+	o := &exportOptions{format: ExportCSV}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	input := struct {
+		Fields            []string               `json:"fields,omitempty"`
+		Format            ExportFormat           `json:"format"`
+		ColumnHeaderNames map[string]string      `json:"columnHeaderNames,omitempty"`
+		Filter            map[string]interface{} `json:"filter"`
+	}{
+		Fields:            fields,
+		Format:            o.format,
+		ColumnHeaderNames: o.headerNames,
+		Filter:            filter,
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost, e.url("/create.json"), bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", "application/json")
+	return e.job(request, createExport, "")
+}
+
+// Enqueue queues a created export job for processing
+func (e *ExportAPI) Enqueue(ctx context.Context, id string) (*ExportJob, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost, e.url(fmt.Sprintf("/%s/enqueue.json", id)), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return e.job(request, enqueueExport, id)
+}
+
+// Get retrieves the status of an export job
+func (e *ExportAPI) Get(ctx context.Context, id string) (*ExportJob, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet, e.url(fmt.Sprintf("/%s/status.json", id)), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return e.job(request, getExport, id)
+}
+
+// List returns the export jobs with any of the provided statuses, or all
+// jobs if none are provided. Marketo limits the number of jobs which may be
+// queued or processing at once, so jobs left queued may need to be
+// cancelled before new ones can be enqueued.
+func (e *ExportAPI) List(ctx context.Context, status []ExportStatus) ([]ExportJob, error) {
+	statuses := make([]string, len(status))
+	for i, s := range status {
+		statuses[i] = string(s)
+	}
+
+	jobs := []ExportJob{}
+	next := ""
+	for {
+		query := url.Values{}
+		if len(statuses) > 0 {
+			query.Set("status", strings.Join(statuses, ","))
+		}
+		if next != "" {
+			query.Set("nextPageToken", next)
+		}
+		request, err := http.NewRequestWithContext(ctx,
+			http.MethodGet, e.url(".json?"+query.Encode()), nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		page := []ExportJob{}
+		response, err := e.c.doResult(request, listExports, &page)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page...)
+		if response.NextPageToken == "" || response.NextPageToken == next {
+			return jobs, nil
+		}
+		next = response.NextPageToken
+	}
+}
+
+// Cancel cancels a queued or processing export job
+func (e *ExportAPI) Cancel(ctx context.Context, id string) error {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost, e.url(fmt.Sprintf("/%s/cancel.json", id)), nil,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = e.job(request, cancelExport, id)
+	return err
+}
+
+// job performs a request returning a single export job, whose ID is
+// included in the error if it is missing from the response
+func (e *ExportAPI) job(request *http.Request, operation, id string) (*ExportJob, error) {
+	jobs := []ExportJob{}
+	_, err := e.c.doResult(request, operation, &jobs)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) < 1 {
+		what := "export job"
+		if id != "" {
+			what += " " + id
+		}
+		return nil, notFoundError{what: what}
+	}
+	return &jobs[0], nil
+}
+
+// File returns the contents of a completed export job's file. The file is
+// streamed from Marketo, and the caller must close it.
+func (e *ExportAPI) File(ctx context.Context, id string) (io.ReadCloser, error) {
+	return e.file(ctx, id, "")
+}
+
+// FileRange returns the bytes of a completed export job's file from start
+// to end, inclusive, allowing an interrupted download to be resumed. If end
+// is negative, the remainder of the file is returned. The caller must close
+// the returned reader.
+func (e *ExportAPI) FileRange(ctx context.Context, id string, start, end int64) (io.ReadCloser, error) {
+	if start < 0 || (end >= 0 && end < start) {
+		return nil, fmt.Errorf("invalid export file range %d-%d", start, end)
+	}
+	if start == 0 && end < 0 {
+		return e.file(ctx, id, "")
+	}
+	byteRange := fmt.Sprintf("bytes=%d-", start)
+	if end >= 0 {
+		byteRange += strconv.FormatInt(end, 10)
+	}
+	return e.file(ctx, id, byteRange)
+}
+
+// Download writes a completed export job's file to w, starting at offset,
+// and returns the offset reached. If the download is interrupted, the
+// returned offset reflects the bytes written so far, and may be passed to
+// Download to continue where it left off.
+func (e *ExportAPI) Download(ctx context.Context, id string, w io.Writer, offset int64) (int64, error) {
+	file, err := e.FileRange(ctx, id, offset, -1)
+	if err != nil {
+		return offset, err
+	}
+	defer file.Close()
+
+	n, err := io.Copy(w, file)
+	return offset + n, err
+}
+
+// file requests an export job's file, optionally restricted to the provided
+// byte range
+func (e *ExportAPI) file(ctx context.Context, id, byteRange string) (io.ReadCloser, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet, e.url(fmt.Sprintf("/%s/file.json", id)), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if byteRange != "" {
+		request.Header.Set("Range", byteRange)
+	}
+
+	resp, err := e.c.doRequest(request, getExportFile)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+		return nil, handleError(getExportFile, resp)
+	}
+	if isJSON(resp) {
+		// errors are returned as a JSON response rather than a file
+		defer resp.Body.Close()
+		response := &Response{}
+		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+			return nil, err
+		}
+		if err := responseError(resp, response); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: unexpected JSON response", getExportFile)
+	}
+	if byteRange != "" && resp.StatusCode != http.StatusPartialContent {
+		// the whole file was returned; reading it from the requested offset
+		// would duplicate data
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: range request not honored", getExportFile)
+	}
+	return resp.Body, nil
+}
+
+// FileStream reads a completed export job's file row by row, calling fn with
+// each record keyed by the file's header. The job's status is fetched first
+// to determine the file's format. The file is not held in memory, so exports
+// of any size can be processed. If fn returns an error, reading stops and
+// the error is returned.
+func (e *ExportAPI) FileStream(ctx context.Context, id string, fn func(record map[string]string) error) error {
+	job, err := e.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	file, err := e.File(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = job.Format.delimiter()
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read export header: %w", err)
+	}
+	header = append([]string{}, header...)
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		record := make(map[string]string, len(header))
+		for i, column := range header {
+			record[column] = row[i]
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
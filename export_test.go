@@ -0,0 +1,365 @@
+package marketo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestExportCreate(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads/export/create.json").
+		JSON(map[string]interface{}{
+			"fields": []string{"email", "firstName"},
+			"format": "CSV",
+			"filter": map[string]interface{}{
+				"createdAt": map[string]string{
+					"startAt": "2021-01-01T00:00:00Z",
+					"endAt":   "2021-01-31T00:00:00Z",
+				},
+			},
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"exportId":"ce45a7a1-f19d-4ce2-882c-a3c795940a7d","format":"CSV","status":"Created","createdAt":"2021-02-01T00:00:00Z"}]}`)
+	gock.New(testHost).
+		Post("/bulk/v1/leads/export/ce45a7a1-f19d-4ce2-882c-a3c795940a7d/enqueue.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"exportId":"ce45a7a1-f19d-4ce2-882c-a3c795940a7d","format":"CSV","status":"Queued","createdAt":"2021-02-01T00:00:00Z","queuedAt":"2021-02-01T00:00:01Z"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewExportAPI(client, LeadExports)
+	job, err := api.Create(context.Background(), []string{"email", "firstName"}, map[string]interface{}{
+		"createdAt": map[string]string{
+			"startAt": "2021-01-01T00:00:00Z",
+			"endAt":   "2021-01-31T00:00:00Z",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ExportCreated, job.Status)
+
+	job, err = api.Enqueue(context.Background(), job.ExportID)
+	require.NoError(t, err)
+	assert.Equal(t, ExportQueued, job.Status)
+	require.NotNil(t, job.QueuedAt)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportFileStream(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/customobjects/testObject_c/export/abc/status.json").
+		Times(2).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"exportId":"abc","format":"CSV","status":"Completed","createdAt":"2021-02-01T00:00:00Z"}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/customobjects/testObject_c/export/abc/file.json").
+		Times(2).
+		Reply(http.StatusOK).
+		SetHeader("Content-Type", "text/csv").
+		BodyString("email,firstName\nnathan@polytomic.com,Nathan\nghalib@polytomic.com,Ghalib\n")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewExportAPI(client, ExportObjectForAPIName("testObject_c"))
+	records := []map[string]string{}
+	err = api.FileStream(context.Background(), "abc", func(record map[string]string) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"email": "nathan@polytomic.com", "firstName": "Nathan"},
+		{"email": "ghalib@polytomic.com", "firstName": "Ghalib"},
+	}, records)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = api.FileStream(context.Background(), "abc", func(record map[string]string) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportFileError(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/export/abc/file.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":false,"errors":[{"code":"1029","message":"Export file not available"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	_, err = NewExportAPI(client, LeadExports).File(context.Background(), "abc")
+	assert.EqualError(t, err, "Export file not available")
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportGetNotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/export/abc/status.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+	})
+	require.NoError(t, err)
+
+	_, err = NewExportAPI(client, LeadExports).Get(context.Background(), "abc")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.EqualError(t, err, "export job abc not found")
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportDownload(t *testing.T) {
+	defer gock.Off()
+
+	const file = "email,firstName\nnathan@polytomic.com,Nathan\n"
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/export/abc/file.json").
+		MatchHeader("Range", "^bytes=16-$").
+		Reply(http.StatusPartialContent).
+		SetHeader("Content-Type", "text/csv").
+		BodyString(file[16:])
+	gock.New(testHost).
+		Get("/bulk/v1/leads/export/abc/file.json").
+		MatchHeader("Range", "^bytes=0-4$").
+		Reply(http.StatusOK).
+		SetHeader("Content-Type", "text/csv").
+		BodyString(file)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewExportAPI(client, LeadExports)
+	out := &strings.Builder{}
+	out.WriteString(file[:16])
+	offset, err := api.Download(context.Background(), "abc", out, 16)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(file)), offset)
+	assert.Equal(t, file, out.String())
+
+	_, err = api.FileRange(context.Background(), "abc", 0, 4)
+	assert.EqualError(t, err, "get export file: range request not honored")
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportListAndCancel(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/bulk/v1/activities/export.json").
+		MatchParam("status", "^Queued,Processing$").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"exportId":"abc","format":"CSV","status":"Queued","createdAt":"2021-02-01T00:00:00Z"}]}`)
+	gock.New(testHost).
+		Post("/bulk/v1/activities/export/abc/cancel.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"exportId":"abc","format":"CSV","status":"Cancelled","createdAt":"2021-02-01T00:00:00Z"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewExportAPI(client, ActivityExports)
+	jobs, err := api.List(context.Background(), []ExportStatus{ExportQueued, ExportProcessing})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, ExportQueued, jobs[0].Status)
+
+	require.NoError(t, api.Cancel(context.Background(), jobs[0].ExportID))
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportFormat(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads/export/create.json").
+		JSON(map[string]interface{}{
+			"fields":            []string{"email"},
+			"format":            "TSV",
+			"columnHeaderNames": map[string]string{"email": "Email Address"},
+			"filter":            map[string]interface{}{"staticListId": 1001},
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"exportId":"abc","format":"TSV","status":"Created","createdAt":"2021-02-01T00:00:00Z"}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/export/abc/status.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"exportId":"abc","format":"TSV","status":"Completed","createdAt":"2021-02-01T00:00:00Z"}]}`)
+	gock.New(testHost).
+		Get("/bulk/v1/leads/export/abc/file.json").
+		Reply(http.StatusOK).
+		SetHeader("Content-Type", "text/plain").
+		BodyString("Email Address\tFirst Name\nnathan@polytomic.com\tNathan, Jr.\n")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewExportAPI(client, LeadExports)
+	job, err := api.Create(context.Background(), []string{"email"},
+		map[string]interface{}{"staticListId": 1001},
+		WithExportFormat(ExportTSV),
+		WithColumnHeaderNames(map[string]string{"email": "Email Address"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ExportTSV, job.Format)
+
+	records := []map[string]string{}
+	err = api.FileStream(context.Background(), job.ExportID, func(record map[string]string) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"Email Address": "nathan@polytomic.com", "First Name": "Nathan, Jr."},
+	}, records)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestExportFileSlowBody(t *testing.T) {
+	const rows = 10000
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/identity/oauth/token":
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+		case "/bulk/v1/leads/export/abc/status.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"success":true,"result":[{"exportId":"abc","format":"CSV","status":"Completed","createdAt":"2021-02-01T00:00:00Z"}]}`))
+		case "/bulk/v1/leads/export/abc/file.json":
+			w.Header().Set("Content-Type", "text/csv")
+			w.Write([]byte("id,email\n"))
+			// stream the rows in chunks, taking longer than the timeouts
+			for chunk := 0; chunk < 4; chunk++ {
+				w.(http.Flusher).Flush()
+				time.Sleep(400 * time.Millisecond)
+				for i := chunk * rows / 4; i < (chunk+1)*rows/4; i++ {
+					fmt.Fprintf(w, "%d,lead%d@example.com\n", i, i)
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClientConfig{
+		ID:                    clientID,
+		Secret:                clientSecret,
+		Endpoint:              ts.URL,
+		Timeout:               1,
+		DefaultRequestTimeout: 500 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	api := NewExportAPI(client, LeadExports)
+
+	t.Run("FileStream", func(t *testing.T) {
+		count := 0
+		err := api.FileStream(context.Background(), "abc", func(record map[string]string) error {
+			assert.Equal(t, strconv.Itoa(count), record["id"])
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, rows, count)
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		offset, err := api.Download(context.Background(), "abc", out, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(out.Len()), offset)
+		assert.Equal(t, rows+1, strings.Count(out.String(), "\n"))
+	})
+}
@@ -0,0 +1,83 @@
+package marketo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ImportFailureReasonColumn is the header of the column containing the
+// failure reason in import failure files.
+const ImportFailureReasonColumn = "Import Failure Reason"
+
+// WriteFailuresCSV writes failures to w as CSV, using header to order the
+// columns. Fields missing from a failure are written as empty cells. If
+// header contains ImportFailureReasonColumn, the failure reason is written
+// to that column; omit it to produce a file suitable for re-importing.
+func WriteFailuresCSV(w io.Writer, header []string, failures []LeadImportFailure) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(header))
+	for _, failure := range failures {
+		for i, column := range header {
+			if column == ImportFailureReasonColumn {
+				record[i] = failure.Reason
+				continue
+			}
+
+			value, ok := failure.Fields[column]
+			if !ok || value == nil {
+				record[i] = ""
+				continue
+			}
+			record[i] = fmt.Sprint(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// SummarizeFailures returns the number of failures for each distinct
+// failure reason.
+func SummarizeFailures(failures []LeadImportFailure) map[string]int {
+	summary := map[string]int{}
+	for _, failure := range failures {
+		summary[failure.Reason]++
+	}
+	return summary
+}
+
+var (
+	// failureCode matches the Marketo error code some failure reasons
+	// begin with, such as "1003: " or "1006, "
+	failureCode = regexp.MustCompile(`^\d+\s*[:,]\s*`)
+	// failureField matches the quoted field name in failure reasons such
+	// as "Value for field 'Email' is invalid"
+	failureField = regexp.MustCompile(`(?i)\bfield\s+(?:'([^']+)'|"([^"]+)")`)
+)
+
+// FieldError extracts the name of the field responsible for the failure from
+// its reason, when the reason follows one of Marketo's common patterns, such
+// as "Value for field 'Email' is invalid". The reason is returned without
+// any leading error code. ok is false if no field could be found.
+func (f LeadImportFailure) FieldError() (field string, reason string, ok bool) {
+	reason = strings.TrimSpace(failureCode.ReplaceAllString(f.Reason, ""))
+	match := failureField.FindStringSubmatch(reason)
+	if match == nil {
+		return "", reason, false
+	}
+	field = match[1]
+	if field == "" {
+		field = match[2]
+	}
+	return field, reason, true
+}
@@ -0,0 +1,79 @@
+package marketo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFailuresCSV(t *testing.T) {
+	failures := []LeadImportFailure{
+		{
+			Reason: "Invalid email",
+			Fields: map[string]interface{}{"email": "nathan@polytomic", "firstName": "Nathan"},
+		},
+		{
+			Reason: "Value for field 'Company' too long",
+			Fields: map[string]interface{}{"email": "ghalib@polytomic.com", "company": "Polytomic, Inc."},
+		},
+	}
+
+	t.Run("without reason", func(t *testing.T) {
+		out := &strings.Builder{}
+		require.NoError(t, WriteFailuresCSV(out, []string{"email", "firstName", "company"}, failures))
+		assert.Equal(t,
+			"email,firstName,company\n"+
+				"nathan@polytomic,Nathan,\n"+
+				"ghalib@polytomic.com,,\"Polytomic, Inc.\"\n",
+			out.String(),
+		)
+	})
+
+	t.Run("with reason", func(t *testing.T) {
+		out := &strings.Builder{}
+		require.NoError(t, WriteFailuresCSV(out, []string{"email", ImportFailureReasonColumn}, failures))
+		assert.Equal(t,
+			"email,Import Failure Reason\n"+
+				"nathan@polytomic,Invalid email\n"+
+				"ghalib@polytomic.com,Value for field 'Company' too long\n",
+			out.String(),
+		)
+	})
+}
+
+func TestSummarizeFailures(t *testing.T) {
+	summary := SummarizeFailures([]LeadImportFailure{
+		{Reason: "Invalid email"},
+		{Reason: "Invalid email"},
+		{Reason: "Value for field 'Company' too long"},
+	})
+
+	assert.Equal(t, map[string]int{
+		"Invalid email":                      2,
+		"Value for field 'Company' too long": 1,
+	}, summary)
+}
+
+func TestFailureFieldError(t *testing.T) {
+	cases := []struct {
+		reason string
+		field  string
+		msg    string
+		ok     bool
+	}{
+		{"1003:Value for field 'Email' is invalid", "Email", "Value for field 'Email' is invalid", true},
+		{"1006, Field \"leadScore\" not found", "leadScore", "Field \"leadScore\" not found", true},
+		{"Value for field 'First Name' exceeds max length", "First Name", "Value for field 'First Name' exceeds max length", true},
+		{"1004: Lead not found", "", "Lead not found", false},
+	}
+	for _, c := range cases {
+		t.Run(c.reason, func(t *testing.T) {
+			field, msg, ok := LeadImportFailure{Reason: c.reason}.FieldError()
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.field, field)
+			assert.Equal(t, c.msg, msg)
+		})
+	}
+}
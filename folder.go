@@ -0,0 +1,153 @@
+package marketo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Folder types used to identify folders & programs in FolderReference
+const (
+	FolderTypeFolder  = "Folder"
+	FolderTypeProgram = "Program"
+)
+
+// AssetFolder identifies the folder an asset is stored in, as returned by the
+// asset API.
+type AssetFolder struct {
+	Type       string `json:"type"`
+	Value      int    `json:"value"`
+	FolderName string `json:"folderName,omitempty"`
+}
+
+// FolderReference identifies a folder or program when creating assets.
+type FolderReference struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+}
+
+// Folder is a Marketo asset folder
+type Folder struct {
+	ID          int              `json:"id"`
+	FolderID    FolderReference  `json:"folderId"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	FolderType  string           `json:"folderType"`
+	Parent      *FolderReference `json:"parent,omitempty"`
+	Path        string           `json:"path"`
+	URL         string           `json:"url"`
+	Workspace   string           `json:"workspace"`
+	IsArchive   bool             `json:"isArchive"`
+	IsSystem    bool             `json:"isSystem"`
+	CreatedAt   string           `json:"createdAt"`
+	UpdatedAt   string           `json:"updatedAt"`
+}
+
+// FolderRequest contains the parameters for creating a folder
+type FolderRequest struct {
+	Name        string
+	Parent      FolderReference
+	Description string
+}
+
+const (
+	browseFolders = "browse folders"
+	getFolder     = "get folder"
+	createFolder  = "create folder"
+)
+
+// FoldersAPI provides access to the Marketo folders asset API
+type FoldersAPI struct {
+	c *Client
+}
+
+// NewFoldersAPI returns a new instance of the folders API, configured with
+// the provided Client.
+func NewFoldersAPI(c *Client) *FoldersAPI {
+	return &FoldersAPI{c: c}
+}
+
+// Browse returns the folders beneath the root provided by WithRoot, or
+// beneath the top level folders if none is provided.
+func (f *FoldersAPI) Browse(ctx context.Context, opts ...AssetQueryOption) ([]Folder, error) {
+	q := AssetQuery{}
+	for _, opt := range opts {
+		opt(&q)
+	}
+
+	folders := []Folder{}
+	err := f.c.pageAssets(ctx, browseFolders,
+		f.c.url("rest", "asset", "v1", "folders.json"), q,
+		func(result json.RawMessage) (int, error) {
+			page := []Folder{}
+			if err := json.Unmarshal(result, &page); err != nil {
+				return 0, err
+			}
+			folders = append(folders, page...)
+			return len(page), nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// Get returns the folder with the provided ID
+func (f *FoldersAPI) Get(ctx context.Context, id int) (*Folder, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		f.c.url("rest", "asset", "v1", "folder", fmt.Sprintf("%d.json?type=%s", id, FolderTypeFolder)),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := []Folder{}
+	_, err = f.c.doResult(request, getFolder, &folders)
+	if err != nil {
+		return nil, err
+	}
+	if len(folders) == 0 {
+		return nil, ErrObjectNotFound
+	}
+	return &folders[0], nil
+}
+
+// Create creates a new folder, returning it
+func (f *FoldersAPI) Create(ctx context.Context, folder FolderRequest) (*Folder, error) {
+	parent, err := json.Marshal(folder.Parent)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("name", folder.Name)
+	form.Set("parent", string(parent))
+	if folder.Description != "" {
+		form.Set("description", folder.Description)
+	}
+
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		f.c.url("rest", "asset", "v1", "folders.json"),
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	folders := []Folder{}
+	_, err = f.c.doResult(request, createFolder, &folders)
+	if err != nil {
+		return nil, err
+	}
+	if len(folders) == 0 {
+		return nil, ErrObjectNotFound
+	}
+	return &folders[0], nil
+}
@@ -0,0 +1,96 @@
+package marketo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestBrowseFolders(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/asset/v1/folders.json").
+		MatchParam("root", `{"id":15,"type":"Folder"}`).
+		MatchParam("maxDepth", "2").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"result": [
+				{
+					"id": 1035,
+					"folderId": {"id": 1035, "type": "Folder"},
+					"name": "Webinars",
+					"folderType": "Marketing Folder",
+					"parent": {"id": 15, "type": "Folder"},
+					"path": "/Marketing Activities/Default/Webinars",
+					"workspace": "Default"
+				}
+			]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewFoldersAPI(client)
+	folders, err := api.Browse(context.Background(),
+		WithRoot(FolderReference{ID: 15, Type: FolderTypeFolder}),
+		WithMaxDepth(2),
+	)
+	require.NoError(t, err)
+	require.Len(t, folders, 1)
+	assert.Equal(t, "Webinars", folders[0].Name)
+	assert.Equal(t, 15, folders[0].Parent.ID)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestCreateFolder(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/asset/v1/folders.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "Webinars", r.PostForm.Get("name"))
+			assert.JSONEq(t, `{"id":15,"type":"Folder"}`, r.PostForm.Get("parent"))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":1035,"name":"Webinars","parent":{"id":15,"type":"Folder"}}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewFoldersAPI(client)
+	folder, err := api.Create(context.Background(), FolderRequest{
+		Name:   "Webinars",
+		Parent: FolderReference{ID: 15, Type: FolderTypeFolder},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1035, folder.ID)
+
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,145 @@
+package marketo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"sync"
+	"time"
+)
+
+// ErrImportInProgress is returned by Create when an import with the same
+// idempotency key and contents has been claimed by another call which has
+// not yet finished submitting it.
+var ErrImportInProgress = errors.New("an identical import is already being submitted")
+
+// ImportStore records the imports submitted by Create, so that an identical
+// file is not submitted twice. Implementations backed by a shared store,
+// such as a database, allow duplicates to be detected across processes.
+//
+// Claim must be atomic: when concurrent calls claim the same key, only one
+// may succeed, or both calls could submit the file.
+type ImportStore interface {
+	// Claim returns the batches recorded for key, if any. Otherwise it
+	// reserves key for the caller, returning true, unless key is already
+	// reserved, in which case it returns ErrImportInProgress.
+	Claim(ctx context.Context, key string) ([]BatchResult, bool, error)
+	// Put records the batches created for a claimed key
+	Put(ctx context.Context, key string, batches []BatchResult) error
+	// Release removes the reservation of a claimed key whose import
+	// could not be submitted, so that it may be retried
+	Release(ctx context.Context, key string) error
+}
+
+// WithIdempotencyKey causes Create to skip submitting a file which was
+// already submitted with the same key, returning the batches recorded in
+// store instead. Concurrent calls submitting the same file are detected
+// too: one submits it while the others return ErrImportInProgress. The key
+// stored combines the provided key with a hash of
+// the object, options, and file contents, so a changed file is submitted
+// even if the key is reused.
+func WithIdempotencyKey(key string, store ImportStore) ImportOption {
+	return func(o *importOptions) {
+		o.idempotencyKey = key
+		o.store = store
+	}
+}
+
+// importHash returns a hash of the import's object and parameters, to which
+// the file contents are written
+func importHash(obj ImportObject, o *importOptions) hash.Hash {
+	h := sha256.New()
+	h.Write([]byte(obj.create))
+	h.Write([]byte{0})
+	h.Write([]byte(o.params.Encode()))
+	h.Write([]byte{0})
+	return h
+}
+
+// importStoreKey returns the key an import is recorded under
+func importStoreKey(key string, h hash.Hash) string {
+	return key + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryImportStore is an ImportStore which records imports in memory for a
+// limited time, measured using the clock of the client calling it.
+type MemoryImportStore struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	imports map[string]storedImport
+}
+
+// storedImport is a recorded import, or a pending one which has been
+// claimed but not yet submitted
+type storedImport struct {
+	batches []BatchResult
+	pending bool
+	expires time.Time
+}
+
+// NewMemoryImportStore returns a MemoryImportStore which remembers imports
+// for ttl after they are submitted.
+func NewMemoryImportStore(ttl time.Duration) *MemoryImportStore {
+	return &MemoryImportStore{
+		ttl:     ttl,
+		imports: map[string]storedImport{},
+	}
+}
+
+// Claim fulfills the ImportStore interface. Claims which are never
+// released or recorded expire after the store's ttl.
+func (s *MemoryImportStore) Claim(ctx context.Context, key string) ([]BatchResult, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := contextClock(ctx).Now()
+	s.expire(now)
+	if stored, ok := s.imports[key]; ok {
+		if stored.pending {
+			return nil, false, ErrImportInProgress
+		}
+		return stored.batches, false, nil
+	}
+	s.imports[key] = storedImport{
+		pending: true,
+		expires: now.Add(s.ttl),
+	}
+	return nil, true, nil
+}
+
+// Put fulfills the ImportStore interface
+func (s *MemoryImportStore) Put(ctx context.Context, key string, batches []BatchResult) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := contextClock(ctx).Now()
+	s.expire(now)
+	s.imports[key] = storedImport{
+		batches: batches,
+		expires: now.Add(s.ttl),
+	}
+	return nil
+}
+
+// Release fulfills the ImportStore interface
+func (s *MemoryImportStore) Release(ctx context.Context, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if stored, ok := s.imports[key]; ok && stored.pending {
+		delete(s.imports, key)
+	}
+	return nil
+}
+
+// expire removes imports recorded more than the store's ttl before now;
+// s.lock must be held
+func (s *MemoryImportStore) expire(now time.Time) {
+	for k, stored := range s.imports {
+		if now.After(stored.expires) {
+			delete(s.imports, k)
+		}
+	}
+}
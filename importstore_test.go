@@ -0,0 +1,222 @@
+package marketo
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestImportIdempotencyKey(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			_, err := ioutil.ReadAll(r.Body)
+			return err == nil, err
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":1,"status":"Importing"}]}`)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			_, err := ioutil.ReadAll(r.Body)
+			return err == nil, err
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":2,"status":"Importing"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	store := NewMemoryImportStore(time.Hour)
+	create := func(file string) int {
+		batches, err := api.Create(context.Background(), Leads,
+			strings.NewReader(file),
+			WithIdempotencyKey("nightly", store),
+		)
+		require.NoError(t, err)
+		require.Len(t, batches, 1)
+		return batches[0].BatchID
+	}
+
+	assert.Equal(t, 1, create("email\nnathan@polytomic.com\n"))
+	assert.Equal(t, 1, create("email\nnathan@polytomic.com\n"))
+	assert.Equal(t, 2, create("email\nghalib@polytomic.com\n"))
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestImportIdempotencyKeyReleasedOnFailure(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":false,"errors":[{"code":"1003","message":"Invalid file"}]}`)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"batchId":1,"status":"Importing"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	store := NewMemoryImportStore(time.Hour)
+	create := func() ([]BatchResult, error) {
+		return api.Create(context.Background(), Leads,
+			strings.NewReader("email\nnathan@polytomic.com\n"),
+			WithIdempotencyKey("nightly", store),
+		)
+	}
+
+	_, err = create()
+	require.Error(t, err)
+	assert.NotEqual(t, ErrImportInProgress, err)
+
+	// the failed import's claim was released, so it can be retried
+	batches, err := create()
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, 1, batches[0].BatchID)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestMemoryImportStoreClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx := withClock(context.Background(), clock)
+	store := NewMemoryImportStore(time.Hour)
+
+	require.NoError(t, store.Put(ctx, "key", []BatchResult{{BatchID: 1}}))
+	clock.Advance(59 * time.Minute)
+	batches, claimed, err := store.Claim(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, []BatchResult{{BatchID: 1}}, batches)
+
+	clock.Advance(2 * time.Minute)
+	_, claimed, err = store.Claim(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestMemoryImportStoreClaim(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryImportStore(time.Hour)
+
+	var (
+		wg      sync.WaitGroup
+		claims  int32
+		pending int32
+	)
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, claimed, err := store.Claim(ctx, "key")
+			if claimed {
+				atomic.AddInt32(&claims, 1)
+			}
+			if err == ErrImportInProgress {
+				atomic.AddInt32(&pending, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), claims)
+	assert.Equal(t, int32(9), pending)
+
+	// a released claim may be claimed again
+	require.NoError(t, store.Release(ctx, "key"))
+	_, claimed, err := store.Claim(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestImportIdempotencyKeyConcurrent(t *testing.T) {
+	var submitted int32
+	entered, release := make(chan struct{}), make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/identity/oauth/token":
+			w.Write([]byte(fmt.Sprintf(authResponseSuccess, token)))
+		case "/bulk/v1/leads.json":
+			ioutil.ReadAll(r.Body)
+			atomic.AddInt32(&submitted, 1)
+			close(entered)
+			<-release
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"success":true,"result":[{"batchId":1,"status":"Importing"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: ts.URL,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	store := NewMemoryImportStore(time.Hour)
+	create := func() ([]BatchResult, error) {
+		return api.Create(context.Background(), Leads,
+			strings.NewReader("email\nnathan@polytomic.com\n"),
+			WithIdempotencyKey("nightly", store),
+		)
+	}
+
+	first := make(chan error)
+	go func() {
+		_, err := create()
+		first <- err
+	}()
+
+	// a second call made while the first is being submitted does not
+	// submit the file again
+	<-entered
+	_, err = create()
+	assert.Equal(t, ErrImportInProgress, err)
+
+	close(release)
+	require.NoError(t, <-first)
+	batches, err := create()
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, 1, batches[0].BatchID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&submitted))
+}
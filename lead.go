@@ -1,11 +1,13 @@
 package marketo
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -51,17 +53,56 @@ type LeadAttribute2 struct {
 	Searchable bool `json:"searchable,omitempty"`
 }
 
-// leadDescribe2Response contains the envelope used to deserialize a call to
-// describe2.json
-type leadDescribe2Response struct {
-	Name             string
-	SearchableFields [][]string
-	Fields           []LeadAttribute2
+// LeadMetadata describes the Lead object, including the keys used to
+// search and dedupe leads, as returned by the describe2.json endpoint.
+type LeadMetadata struct {
+	Name             string           `json:"name"`
+	IDField          string           `json:"idField"`
+	DedupeFields     []string         `json:"dedupeFields"`
+	SearchableFields [][]string       `json:"searchableFields"`
+	Fields           []LeadAttribute2 `json:"fields"`
+}
+
+// LeadSchemaField defines a lead field as returned by the lead schema
+// fields endpoint. Name is the field's REST API name.
+type LeadSchemaField struct {
+	Name                  string `json:"name"`
+	DisplayName           string `json:"displayName"`
+	Description           string `json:"description,omitempty"`
+	DataType              string `json:"dataType"`
+	Length                int    `json:"length,omitempty"`
+	IsHidden              bool   `json:"isHidden"`
+	IsHTMLEncodingInEmail bool   `json:"isHtmlEncodingInEmail"`
+	IsSensitive           bool   `json:"isSensitive"`
+	IsCustom              bool   `json:"isCustom"`
+	IsAPICreated          bool   `json:"isApiCreated"`
+}
+
+// LeadFieldDefinition defines a custom lead field to create
+type LeadFieldDefinition struct {
+	Name                  string `json:"name"`
+	DisplayName           string `json:"displayName"`
+	Description           string `json:"description,omitempty"`
+	DataType              string `json:"dataType"`
+	Length                int    `json:"length,omitempty"`
+	IsHidden              bool   `json:"isHidden,omitempty"`
+	IsHTMLEncodingInEmail bool   `json:"isHtmlEncodingInEmail,omitempty"`
+	IsSensitive           bool   `json:"isSensitive,omitempty"`
+}
+
+// leadFieldResult contains the per-field result of creating a lead field
+type leadFieldResult struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Reasons []Reason `json:"reasons,omitempty"`
 }
 
 const (
-	describeLead2 = "describe2 lead"
-	filterLeads   = "filter leads"
+	describeLead2   = "describe2 lead"
+	filterLeads     = "filter leads"
+	listLeadSchema  = "list lead schema fields"
+	createLeadField = "create lead field"
+	deleteLeads     = "delete leads"
 )
 
 // LeadAPI provides access to the Marketo Lead API
@@ -75,91 +116,151 @@ func NewLeadAPI(c *Client) *LeadAPI {
 	return &LeadAPI{c: c}
 }
 
+func (l *LeadAPI) object() objectAPI {
+	return objectAPI{c: l.c, path: "leads"}
+}
+
+// Describe fetches the Lead schema from Marketo, including the fields and
+// the dedupe and searchable keys
+func (l *LeadAPI) Describe(ctx context.Context) (*LeadMetadata, error) {
+	object := []LeadMetadata{}
+	err := l.object().describe(ctx, describeLead2, "describe2.json", &object)
+	if err != nil {
+		return nil, err
+	}
+	if len(object) == 0 {
+		return nil, ErrObjectNotFound
+	}
+
+	names := searchableNames(object[0].SearchableFields)
+	for i := range object[0].Fields {
+		object[0].Fields[i].Searchable = names[object[0].Fields[i].Name]
+	}
+	return &object[0], nil
+}
+
 // DescribeFields fetches the Lead schema from Marketo and returns the set of
 // attributes defined
 func (l *LeadAPI) DescribeFields(ctx context.Context) ([]LeadAttribute2, error) {
-	request, err := http.NewRequest(
-		http.MethodGet, l.c.url("rest", "v1", "leads", "describe2.json"), nil,
-	)
+	object, err := l.Describe(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return object.Fields, nil
+}
+
+// SchemaFields returns a page of lead field definitions, along with the
+// token for the next page, if any. Only the BatchSize and GetPage options are
+// used.
+func (l *LeadAPI) SchemaFields(ctx context.Context, opts ...QueryOption) ([]LeadSchemaField, string, error) {
+	q := &Query{}
+	for _, opt := range opts {
+		opt(q)
+	}
 
-	resp, err := l.c.doRequest(request)
+	query := url.Values{}
+	if q.BatchSize > 0 {
+		query.Set("batchSize", strconv.Itoa(q.BatchSize))
+	}
+	if q.NextPageToken != "" {
+		query.Set("nextPageToken", q.NextPageToken)
+	}
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		l.c.url("rest", "v1", "leads", "schema", "fields.json?"+query.Encode()),
+		nil,
+	)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	resp, err := l.c.doRequest(request, listLeadSchema)
+	if err != nil {
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, handleError(describeLead2, resp)
+		return nil, "", handleError(listLeadSchema, resp)
 	}
 
 	response := &Response{}
 	reader := json.NewDecoder(resp.Body)
 	err = reader.Decode(response)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-
-	object := []leadDescribe2Response{}
-	err = json.Unmarshal(response.Result, &object)
-	if len(object) == 0 {
-		return nil, errors.New("not found")
+	if err = responseError(resp, response); err != nil {
+		return nil, "", err
 	}
 
-	searchable := map[string]bool{}
-	for _, s := range object[0].SearchableFields {
-		for _, fld := range s {
-			searchable[fld] = true
-		}
+	fields := []LeadSchemaField{}
+	err = json.Unmarshal(response.Result, &fields)
+	if err != nil {
+		return nil, "", err
 	}
 
-	for i, field := range object[0].Fields {
-		field.Searchable = searchable[field.Name]
-		object[0].Fields[i] = field
-	}
-	return object[0].Fields, err
+	return fields, response.NextPageToken, nil
 }
 
-// Filter queries Marketo for one or more Leads, returning them if present
-func (l *LeadAPI) Filter(ctx context.Context, opts ...QueryOption) ([]LeadResult, string, error) {
-	q := &Query{}
-	for _, opt := range opts {
-		opt(q)
-	}
-
-	query, err := q.Values()
+// CreateField creates a new custom lead field
+func (l *LeadAPI) CreateField(ctx context.Context, field LeadFieldDefinition) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"input": []LeadFieldDefinition{field},
+	})
 	if err != nil {
-		return nil, "", err
+		return err
 	}
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(ctx,
 		http.MethodPost,
-		l.c.url("rest", "v1", "leads.json?_method=GET"),
-		strings.NewReader(query.Encode()),
+		l.c.url("rest", "v1", "leads", "schema", "fields.json"),
+		bytes.NewReader(body),
 	)
-	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	if err != nil {
-		return nil, "", err
+		return err
 	}
+	request.Header.Add("Content-Type", "application/json")
 
-	resp, err := l.c.doRequest(request)
+	resp, err := l.c.doRequest(request, createLeadField)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, "", handleError(filterLeads, resp)
+		return handleError(createLeadField, resp)
 	}
 
 	response := &Response{}
 	reader := json.NewDecoder(resp.Body)
 	err = reader.Decode(response)
 	if err != nil {
-		return nil, "", err
+		return err
+	}
+	if err = responseError(resp, response); err != nil {
+		return err
+	}
+
+	results := []leadFieldResult{}
+	err = json.Unmarshal(response.Result, &results)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if len(result.Reasons) > 0 {
+			return ErrorForReasons(resp.StatusCode, result.Reasons...)
+		}
+	}
+
+	return nil
+}
+
+// Filter queries Marketo for one or more Leads, returning them if present
+func (l *LeadAPI) Filter(ctx context.Context, opts ...QueryOption) ([]LeadResult, string, error) {
+	q := &Query{}
+	for _, opt := range opts {
+		opt(q)
 	}
 
-	raw := []map[string]interface{}{}
-	err = json.Unmarshal(response.Result, &raw)
+	raw, next, err := l.object().filter(ctx, filterLeads, q)
 	if err != nil {
 		return nil, "", err
 	}
@@ -172,5 +273,78 @@ func (l *LeadAPI) Filter(ctx context.Context, opts ...QueryOption) ([]LeadResult
 		}
 	}
 
-	return leads, response.NextPageToken, nil
+	return leads, next, nil
+}
+
+// Delete deletes the leads with the provided IDs, returning the result for
+// each. At most MaximumQueryBatchSize leads may be deleted in a single call.
+func (l *LeadAPI) Delete(ctx context.Context, ids []int) ([]RecordResult, error) {
+	if len(ids) > MaximumQueryBatchSize {
+		return nil, fmt.Errorf("too many leads: %d exceeds the maximum of %d",
+			len(ids), MaximumQueryBatchSize)
+	}
+
+	type leadID struct {
+		ID int `json:"id"`
+	}
+	input := struct {
+		Input []leadID `json:"input"`
+	}{}
+	for _, id := range ids {
+		input.Input = append(input.Input, leadID{id})
+	}
+
+	results := []RecordResult{}
+	err := l.object().delete(ctx, deleteLeads, input, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DeleteByFilter deletes the leads whose lookupField matches any of values,
+// for example their email addresses, returning the result for each lead
+// deleted. The matching lead IDs are found with Filter, and deleted in
+// batches of MaximumQueryBatchSize.
+func (l *LeadAPI) DeleteByFilter(ctx context.Context, lookupField string, values []string) ([]RecordResult, error) {
+	ids := []int{}
+	for _, batch := range FilterBatches(values) {
+		next := ""
+		for {
+			opts := []QueryOption{
+				FilterField(lookupField),
+				FilterValues(batch),
+				GetFields("id"),
+			}
+			if next != "" {
+				opts = append(opts, GetPage(next))
+			}
+
+			leads, token, err := l.Filter(ctx, opts...)
+			if err != nil {
+				return nil, err
+			}
+			for _, lead := range leads {
+				ids = append(ids, lead.ID)
+			}
+			if token == "" {
+				break
+			}
+			next = token
+		}
+	}
+
+	results := []RecordResult{}
+	for start := 0; start < len(ids); start += MaximumQueryBatchSize {
+		end := start + MaximumQueryBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		deleted, err := l.Delete(ctx, ids[start:end])
+		if err != nil {
+			return results, err
+		}
+		results = append(results, deleted...)
+	}
+	return results, nil
 }
@@ -2,6 +2,7 @@ package marketo
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"testing"
 
@@ -126,3 +127,172 @@ func TestFilterLeads_withFields(t *testing.T) {
 
 	assert.True(t, gock.IsDone())
 }
+
+func TestLeadDescribeMetadata(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusOK).
+		File("test-fixtures/leads-describe2.json")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewLeadAPI(client)
+	lead, err := api.Describe(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "API Lead", lead.Name)
+	assert.Equal(t, "id", lead.IDField)
+	assert.Equal(t, []string{"email"}, lead.DedupeFields)
+	assert.Len(t, lead.SearchableFields, 9)
+	assert.Len(t, lead.Fields, 90)
+
+	// each searchable key of the fixture is a single field
+	searchable := []string{}
+	for _, f := range lead.Fields {
+		if f.Searchable {
+			searchable = append(searchable, f.Name)
+		}
+	}
+	assert.Len(t, searchable, 9)
+	assert.Contains(t, searchable, "email")
+	assert.NotContains(t, searchable, "firstName")
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestLeadSchemaFields(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/leads/schema/fields.json").
+		MatchParam("batchSize", "2").
+		MatchParam("nextPageToken", "abc").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"nextPageToken": "def",
+			"result": [
+				{"name": "email", "displayName": "Email Address", "dataType": "email", "length": 255},
+				{"name": "score_c", "displayName": "Score", "dataType": "integer", "isCustom": true, "isApiCreated": true}
+			]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewLeadAPI(client)
+	fields, next, err := api.SchemaFields(context.Background(), BatchSize(2), GetPage("abc"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "def", next)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "email", fields[0].Name)
+	assert.True(t, fields[1].IsCustom)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestLeadCreateField(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/leads/schema/fields.json").
+		JSON(map[string]interface{}{
+			"input": []map[string]interface{}{
+				{"name": "score_c", "displayName": "Score", "dataType": "integer"},
+			},
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"name":"score_c","status":"skipped","reasons":[{"code":"1006","message":"Field 'score_c' already exists"}]}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewLeadAPI(client)
+	err = api.CreateField(context.Background(), LeadFieldDefinition{
+		Name:        "score_c",
+		DisplayName: "Score",
+		DataType:    "integer",
+	})
+	assert.EqualError(t, err, "Field 'score_c' already exists")
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestDeleteLeadsByFilter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/leads.json").
+		MatchParam("_method", "GET").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "email", r.PostForm.Get("filterType"))
+			assert.Equal(t, "id", r.PostForm.Get("fields"))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":318581},{"id":318582}]}`)
+	gock.New(testHost).
+		Post("/rest/v1/leads/delete.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"input":[{"id":318581},{"id":318582}]}`, string(body))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":318581,"status":"deleted"},{"id":318582,"status":"deleted"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewLeadAPI(client)
+	results, err := api.DeleteByFilter(context.Background(), "email",
+		[]string{"nathan@polytomic.com", "ghalib@polytomic.com"},
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "deleted", results[1].Status)
+
+	assert.True(t, gock.IsDone())
+}
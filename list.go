@@ -2,6 +2,7 @@ package marketo
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -20,6 +21,25 @@ type Query struct {
 	Fields        []string `json:"fields,omitempty"`
 	BatchSize     int      `json:"batchSize,omitempty"`
 	NextPageToken string   `json:"nextPageToken,omitempty"`
+	// RawParams are added to the encoded query as-is, overriding any
+	// values set by the other fields
+	RawParams url.Values `json:"rawParams,omitempty"`
+	// MaxRecords limits the number of records returned when paging
+	// automatically, such as by FilterAll; it is not sent to Marketo
+	MaxRecords int `json:"maxRecords,omitempty"`
+}
+
+// checkFilter returns an error if the query has no filter field or values.
+// Queries which page through every batch of values check this up front,
+// since with no values there would be no batches to query.
+func (q *Query) checkFilter() error {
+	if q.FilterField == "" {
+		return errors.New("no filter field")
+	}
+	if len(q.FilterValues) < 1 {
+		return errors.New("too few values")
+	}
+	return nil
 }
 
 // Values returns the query payload as url.Values; if the query is invalid, an
@@ -34,7 +54,10 @@ func (q *Query) Values() (url.Values, error) {
 		return result, errors.New("too few values")
 	}
 	if len(q.FilterValues) > MaximumQueryBatchSize {
-		return result, errors.New("too many values")
+		return result, fmt.Errorf(
+			"too many values: %d filter values exceeds the maximum of %d; use FilterBatches to split them",
+			len(q.FilterValues), MaximumQueryBatchSize,
+		)
 	}
 
 	values := url.Values{}
@@ -47,10 +70,27 @@ func (q *Query) Values() (url.Values, error) {
 	if q.NextPageToken != "" {
 		values.Set("nextPageToken", q.NextPageToken)
 	}
+	for key, v := range q.RawParams {
+		values[key] = v
+	}
 
 	return values, nil
 }
 
+// FilterBatches splits filter values into batches of at most
+// MaximumQueryBatchSize, the most Marketo accepts in a single query.
+func FilterBatches(values []string) [][]string {
+	var batches [][]string
+	for start := 0; start < len(values); start += MaximumQueryBatchSize {
+		end := start + MaximumQueryBatchSize
+		if end > len(values) {
+			end = len(values)
+		}
+		batches = append(batches, values[start:end])
+	}
+	return batches
+}
+
 // QueryOption defines the signature of functional options for Marketo Query
 // APIs.
 type QueryOption func(*Query)
@@ -82,3 +122,30 @@ func GetPage(t string) QueryOption {
 		q.NextPageToken = t
 	}
 }
+
+// BatchSize sets the number of records to return per page
+func BatchSize(size int) QueryOption {
+	return func(q *Query) {
+		q.BatchSize = size
+	}
+}
+
+// WithMaxRecords limits the number of records returned when paging
+// automatically, stopping once n records have been retrieved even if more
+// pages exist.
+func WithMaxRecords(n int) QueryOption {
+	return func(q *Query) {
+		q.MaxRecords = n
+	}
+}
+
+// WithRawParam adds a parameter to the query which is not otherwise
+// supported, such as one recently added by Marketo.
+func WithRawParam(key, value string) QueryOption {
+	return func(q *Query) {
+		if q.RawParams == nil {
+			q.RawParams = url.Values{}
+		}
+		q.RawParams.Add(key, value)
+	}
+}
@@ -0,0 +1,41 @@
+package marketo
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryValues(t *testing.T) {
+	q := &Query{}
+	for _, opt := range []QueryOption{
+		FilterField("email"),
+		FilterValues([]string{"nathan@polytomic.com"}),
+		WithRawParam("partitionName", "Default"),
+	} {
+		opt(q)
+	}
+
+	values, err := q.Values()
+	require.NoError(t, err)
+	assert.Equal(t, "email", values.Get("filterType"))
+	assert.Equal(t, "Default", values.Get("partitionName"))
+}
+
+func TestQueryTooManyValues(t *testing.T) {
+	values := make([]string, MaximumQueryBatchSize+1)
+	for i := range values {
+		values[i] = strconv.Itoa(i)
+	}
+
+	q := &Query{FilterField: "id", FilterValues: values}
+	_, err := q.Values()
+	assert.EqualError(t, err, "too many values: 301 filter values exceeds the maximum of 300; use FilterBatches to split them")
+
+	batches := FilterBatches(values)
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], MaximumQueryBatchSize)
+	assert.Equal(t, []string{"300"}, batches[1])
+}
@@ -0,0 +1,67 @@
+package marketo
+
+import (
+	"net/http"
+	"time"
+)
+
+// ClientStats contains cumulative counts of the calls made by a Client
+type ClientStats struct {
+	// Requests is the number of REST requests sent, including retries
+	Requests int64
+	// Retries is the number of requests which were retried
+	Retries int64
+	// RateLimited is the number of requests rejected because a rate or
+	// concurrency limit was exceeded
+	RateLimited int64
+	// TokenRefreshes is the number of access tokens fetched, including the
+	// one fetched when the client was created
+	TokenRefreshes int64
+	// LastRequestAt is the time the most recent request was sent, and
+	// LastStatus the HTTP status it received, or 0 if it failed
+	LastRequestAt time.Time
+	LastStatus    int
+}
+
+// Stats returns the counts of calls made since the client was created
+func (c *Client) Stats() ClientStats {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	return c.stats
+}
+
+// recordRequest records a REST request sent at start, along with its
+// response, which may be nil if the request failed
+func (c *Client) recordRequest(start time.Time, resp *http.Response) {
+	rateLimited := false
+	if resp != nil {
+		rateLimited = resp.StatusCode == http.StatusTooManyRequests ||
+			hasReason(ResponseReasons(resp), ErrRateLimitExceeded, ErrConcurrentLimitReached)
+	}
+
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	c.stats.Requests++
+	c.stats.LastRequestAt = start
+	c.stats.LastStatus = 0
+	if resp != nil {
+		c.stats.LastStatus = resp.StatusCode
+	}
+	if rateLimited {
+		c.stats.RateLimited++
+	}
+}
+
+// recordRetry records a request being retried
+func (c *Client) recordRetry() {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	c.stats.Retries++
+}
+
+// recordTokenRefresh records an access token being fetched
+func (c *Client) recordTokenRefresh() {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	c.stats.TokenRefreshes++
+}
@@ -0,0 +1,94 @@
+package marketo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// objectAPI implements the calls shared by Marketo's lead database objects,
+// such as leads and custom objects, which support the same filter, sync,
+// and describe shape at rest/v1/{path}. New objects can be supported by
+// wrapping an objectAPI with the appropriate path and result types.
+type objectAPI struct {
+	c    *Client
+	path string
+}
+
+// url returns the URL of the object's endpoint, with the provided suffix
+// (such as ".json" or "/describe.json") appended
+func (o objectAPI) url(suffix string) string {
+	return o.c.url("rest", "v1", o.path+suffix)
+}
+
+// describe fetches the object's schema from the provided describe endpoint,
+// decoding the result into result
+func (o objectAPI) describe(ctx context.Context, operation, endpoint string, result interface{}) error {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet, o.url("/"+endpoint), nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.c.doResult(request, operation, result)
+	return err
+}
+
+// filter queries for records matching q, returning each record as a map
+// along with the token for the next page, if any
+func (o objectAPI) filter(ctx context.Context, operation string, q *Query) ([]map[string]interface{}, string, error) {
+	query, err := q.Values()
+	if err != nil {
+		return nil, "", err
+	}
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		o.url(".json?_method=GET"),
+		strings.NewReader(query.Encode()),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	raw := []map[string]interface{}{}
+	response, err := o.c.doResult(request, operation, &raw)
+	if err != nil {
+		return nil, "", err
+	}
+	return raw, response.NextPageToken, nil
+}
+
+// post serializes input as JSON and posts it to the object's endpoint with
+// the provided suffix, decoding the result into result
+func (o objectAPI) post(ctx context.Context, operation, suffix string, input, result interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		o.url(suffix),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Content-Type", "application/json")
+
+	_, err = o.c.doResult(request, operation, result)
+	return err
+}
+
+// sync creates or updates records
+func (o objectAPI) sync(ctx context.Context, operation string, input, result interface{}) error {
+	return o.post(ctx, operation, ".json", input, result)
+}
+
+// delete deletes records
+func (o objectAPI) delete(ctx context.Context, operation string, input, result interface{}) error {
+	return o.post(ctx, operation, "/delete.json", input, result)
+}
@@ -0,0 +1,75 @@
+package marketo
+
+// Operation identifies a kind of API call, such as creating a bulk import.
+// It names the call in errors, and selects the timeout configured for the
+// call in ClientConfig.OperationTimeouts.
+type Operation string
+
+// The operations performed by the client
+const (
+	// client
+	OperationPing Operation = ping
+
+	// activities
+	OperationGetActivityPagingToken Operation = activityPagingToken
+	OperationGetActivities          Operation = getActivities
+	OperationGetActivityTypes       Operation = getActivityTypes
+	OperationGetCustomActivityTypes Operation = getCustomTypes
+	OperationAddCustomActivities    Operation = addCustomActivities
+
+	// bulk imports
+	OperationCreateImport      Operation = createImport
+	OperationGetImport         Operation = getImport
+	OperationGetImportFailures Operation = getImportFailures
+	OperationGetImportWarnings Operation = getImportWarnings
+
+	// campaigns
+	OperationListCampaigns   Operation = listCampaigns
+	OperationGetCampaign     Operation = getCampaign
+	OperationRequestCampaign Operation = requestCampaign
+
+	// custom objects
+	OperationDescribeCustomObject       Operation = describeCustomObject
+	OperationDescribeCustomObjectSchema Operation = describeCustomObjectSchema
+	OperationListCustomObjects          Operation = listCustomObjects
+	OperationApproveCustomObject        Operation = approveCustomObject
+	OperationDiscardCustomObjectDraft   Operation = discardCustomObjectDraft
+	OperationDeleteCustomObjectField    Operation = deleteCustomObjectField
+	OperationDeleteCustomObject         Operation = deleteCustomObject
+	OperationSyncCustomObjects          Operation = syncCustomObjects
+	OperationDeleteCustomObjects        Operation = deleteCustomObjects
+	OperationFilterCustomObjects        Operation = filterCustomObjects
+
+	// bulk exports
+	OperationCreateExport  Operation = createExport
+	OperationEnqueueExport Operation = enqueueExport
+	OperationGetExport     Operation = getExport
+	OperationGetExportFile Operation = getExportFile
+	OperationListExports   Operation = listExports
+	OperationCancelExport  Operation = cancelExport
+
+	// folders
+	OperationBrowseFolders Operation = browseFolders
+	OperationGetFolder     Operation = getFolder
+	OperationCreateFolder  Operation = createFolder
+
+	// leads
+	OperationDescribeLead2   Operation = describeLead2
+	OperationFilterLeads     Operation = filterLeads
+	OperationListLeadSchema  Operation = listLeadSchema
+	OperationCreateLeadField Operation = createLeadField
+	OperationDeleteLeads     Operation = deleteLeads
+
+	// programs
+	OperationListPrograms Operation = listPrograms
+	OperationGetProgram   Operation = getProgram
+	OperationCloneProgram Operation = cloneProgram
+
+	// static lists
+	OperationCreateStaticList Operation = createStaticList
+	OperationDeleteStaticList Operation = deleteStaticList
+
+	// usage statistics
+	OperationGetUsage       Operation = getUsage
+	OperationGetErrorCounts Operation = getErrorCounts
+)
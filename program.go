@@ -0,0 +1,144 @@
+package marketo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Program is a Marketo marketing program
+type Program struct {
+	ID          int         `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	URL         string      `json:"url"`
+	Type        string      `json:"type"`
+	Channel     string      `json:"channel"`
+	Status      string      `json:"status"`
+	Workspace   string      `json:"workspace"`
+	Folder      AssetFolder `json:"folder"`
+	CreatedAt   string      `json:"createdAt"`
+	UpdatedAt   string      `json:"updatedAt"`
+}
+
+// ProgramCloneRequest contains the parameters for cloning a program
+type ProgramCloneRequest struct {
+	Name        string
+	Folder      FolderReference
+	Description string
+}
+
+const (
+	listPrograms = "list programs"
+	getProgram   = "get program"
+	cloneProgram = "clone program"
+)
+
+// ProgramsAPI provides access to the Marketo programs asset API
+type ProgramsAPI struct {
+	c *Client
+}
+
+// NewProgramsAPI returns a new instance of the programs API, configured with
+// the provided Client.
+func NewProgramsAPI(c *Client) *ProgramsAPI {
+	return &ProgramsAPI{c: c}
+}
+
+// List returns the programs matching the provided query
+func (p *ProgramsAPI) List(ctx context.Context, opts ...AssetQueryOption) ([]Program, error) {
+	q := AssetQuery{}
+	for _, opt := range opts {
+		opt(&q)
+	}
+
+	programs := []Program{}
+	err := p.c.pageAssets(ctx, listPrograms,
+		p.c.url("rest", "asset", "v1", "programs.json"), q,
+		func(result json.RawMessage) (int, error) {
+			page := []Program{}
+			if err := json.Unmarshal(result, &page); err != nil {
+				return 0, err
+			}
+			programs = append(programs, page...)
+			return len(page), nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return programs, nil
+}
+
+// Get returns the program with the provided ID
+func (p *ProgramsAPI) Get(ctx context.Context, id int) (*Program, error) {
+	return p.get(ctx,
+		p.c.url("rest", "asset", "v1", "program", fmt.Sprintf("%d.json", id)),
+	)
+}
+
+// GetByName returns the program with the provided name
+func (p *ProgramsAPI) GetByName(ctx context.Context, name string) (*Program, error) {
+	query := url.Values{}
+	query.Set("name", name)
+	return p.get(ctx,
+		p.c.url("rest", "asset", "v1", "program", "byName.json?"+query.Encode()),
+	)
+}
+
+// Clone creates a copy of the program with the provided ID, returning the
+// new program
+func (p *ProgramsAPI) Clone(ctx context.Context, id int, clone ProgramCloneRequest) (*Program, error) {
+	folder, err := json.Marshal(clone.Folder)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("name", clone.Name)
+	form.Set("folder", string(folder))
+	if clone.Description != "" {
+		form.Set("description", clone.Description)
+	}
+
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		p.c.url("rest", "asset", "v1", "program", strconv.Itoa(id), "clone.json"),
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	programs := []Program{}
+	_, err = p.c.doResult(request, cloneProgram, &programs)
+	if err != nil {
+		return nil, err
+	}
+	if len(programs) == 0 {
+		return nil, ErrObjectNotFound
+	}
+	return &programs[0], nil
+}
+
+// get retrieves a single program from the provided endpoint
+func (p *ProgramsAPI) get(ctx context.Context, endpoint string) (*Program, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	programs := []Program{}
+	_, err = p.c.doResult(request, getProgram, &programs)
+	if err != nil {
+		return nil, err
+	}
+	if len(programs) == 0 {
+		return nil, ErrObjectNotFound
+	}
+	return &programs[0], nil
+}
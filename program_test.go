@@ -0,0 +1,200 @@
+package marketo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const programResponse = `{
+	"success": true,
+	"result": [
+		{
+			"id": 1107,
+			"name": "Webinar Template",
+			"type": "Event",
+			"channel": "Webinar",
+			"status": "",
+			"workspace": "Default",
+			"folder": {"type": "Folder", "value": 1035, "folderName": "Webinars"}
+		}
+	]
+}`
+
+func TestListPrograms(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/asset/v1/programs.json").
+		MatchParam("maxReturn", "50").
+		MatchParam("filterType", "folderId").
+		MatchParam("filterValues", "1035").
+		Reply(http.StatusOK).
+		JSON(programResponse)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewProgramsAPI(client)
+	programs, err := api.List(context.Background(),
+		WithMaxReturn(50),
+		AssetFilter("folderId", "1035"),
+	)
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+	assert.Equal(t, "Webinar Template", programs[0].Name)
+	assert.Equal(t, 1035, programs[0].Folder.Value)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestGetProgram(t *testing.T) {
+	t.Run("by name", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/asset/v1/program/byName.json").
+			MatchParam("name", "Webinar Template").
+			Reply(http.StatusOK).
+			JSON(programResponse)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		api := NewProgramsAPI(client)
+		program, err := api.GetByName(context.Background(), "Webinar Template")
+		require.NoError(t, err)
+		assert.Equal(t, 1107, program.ID)
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/asset/v1/program/1.json").
+			Reply(http.StatusOK).
+			JSON(`{"success":true,"warnings":["No assets found for the given search criteria."]}`)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		var warnings []Reason
+		ctx := WithWarningHandler(context.Background(), func(operation string, w []Reason) {
+			warnings = append(warnings, w...)
+		})
+		api := NewProgramsAPI(client)
+		_, err = api.Get(ctx, 1)
+		assert.True(t, errors.Is(err, ErrObjectNotFound))
+		require.Len(t, warnings, 1)
+		assert.Equal(t, "No assets found for the given search criteria.", warnings[0].Message)
+
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestCloneProgram(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/asset/v1/program/1107/clone.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "Q3 Webinar", r.PostForm.Get("name"))
+			assert.JSONEq(t, `{"id":1035,"type":"Folder"}`, r.PostForm.Get("folder"))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(programResponse)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewProgramsAPI(client)
+	program, err := api.Clone(context.Background(), 1107, ProgramCloneRequest{
+		Name:   "Q3 Webinar",
+		Folder: FolderReference{ID: 1035, Type: "Folder"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1107, program.ID)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestListProgramsPaging(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/asset/v1/programs.json").
+		MatchParam("maxReturn", "2").
+		MatchParam("offset", "2").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":3}]}`)
+	gock.New(testHost).
+		Get("/rest/asset/v1/programs.json").
+		MatchParam("maxReturn", "2").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":1},{"id":2}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewProgramsAPI(client)
+	programs, err := api.List(context.Background(), WithMaxReturn(2))
+	require.NoError(t, err)
+	require.Len(t, programs, 3)
+	assert.Equal(t, 3, programs[2].ID)
+
+	assert.True(t, gock.IsDone())
+}
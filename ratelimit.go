@@ -0,0 +1,63 @@
+package marketo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateLimit is the number of calls Marketo permits in each
+	// DefaultRateLimitWindow
+	DefaultRateLimit = 100
+	// DefaultRateLimitWindow is the window over which Marketo enforces its
+	// rate limit
+	DefaultRateLimitWindow = 20 * time.Second
+	// MaximumConcurrentCalls is the number of calls Marketo permits to be
+	// in progress at once
+	MaximumConcurrentCalls = 10
+)
+
+// rateLimiter limits the number of calls made in any rolling window. Unlike a
+// token bucket, which permits a full burst at the end of one window followed
+// by another at the start of the next, this guarantees the limit is never
+// exceeded when measured over any interval of the window's length.
+type rateLimiter struct {
+	lock   sync.Mutex
+	window time.Duration
+	// calls is a ring of the times of the most recent calls
+	calls []time.Time
+	next  int
+	clock Clock
+}
+
+func newRateLimiter(limit int, window time.Duration, clock Clock) *rateLimiter {
+	return &rateLimiter{
+		window: window,
+		calls:  make([]time.Time, limit),
+		clock:  clock,
+	}
+}
+
+// Wait blocks until a call may be made without exceeding the limit, or ctx
+// is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.lock.Lock()
+		now := l.clock.Now()
+		// the oldest call in the ring determines when the next call can be
+		// made
+		available := l.calls[l.next].Add(l.window)
+		if !available.After(now) {
+			l.calls[l.next] = now
+			l.next = (l.next + 1) % len(l.calls)
+			l.lock.Unlock()
+			return nil
+		}
+		l.lock.Unlock()
+
+		if err := sleep(ctx, l.clock, available.Sub(now)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package marketo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(2, 100*time.Millisecond, realClock{})
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background()))
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.Less(t, int64(time.Since(start)), int64(50*time.Millisecond))
+
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	t.Run("respects context", func(t *testing.T) {
+		limiter := newRateLimiter(1, time.Hour, realClock{})
+		require.NoError(t, limiter.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx))
+	})
+
+	t.Run("stops its timer when the context is done", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), frozen: true}
+		limiter := newRateLimiter(1, time.Hour, clock)
+		require.NoError(t, limiter.Wait(context.Background()))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, context.Canceled, limiter.Wait(ctx))
+		require.Len(t, clock.timers, 1)
+		assert.Equal(t, time.Hour, clock.delays[0])
+		assert.True(t, clock.timers[0].stopped)
+	})
+}
@@ -0,0 +1,162 @@
+package marketo
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultNullValue is the value CreateRecords writes for Null
+	DefaultNullValue = "NULL"
+	// dateLayout is the format of date field values
+	dateLayout = "2006-01-02"
+)
+
+// Date is a record value for a date field, which CreateRecords writes as
+// YYYY-MM-DD. time.Time values are written with a time of day, in the format
+// of datetime fields.
+type Date time.Time
+
+// ColumnLimitError is returned by CreateRecords when the records have more
+// columns than the limit set using WithMaxColumns; nothing is uploaded.
+type ColumnLimitError struct {
+	Columns int
+	Limit   int
+}
+
+// Error fulfills the error interface
+func (e *ColumnLimitError) Error() string {
+	return fmt.Sprintf("import has %d columns, exceeding the limit of %d", e.Columns, e.Limit)
+}
+
+type null struct{}
+
+// Null is a record value which clears the field when imported using
+// CreateRecords. Marketo leaves a field unchanged when its cell in an import
+// file is blank, so nil values and missing keys cannot be used to clear a
+// field; Null is written as DefaultNullValue, or the value set using
+// WithNullValue, instead.
+var Null = null{}
+
+// CreateRecords encodes records as a CSV file and uploads it for importing,
+// returning the new asynchronous import. The header is the union of the
+// records' keys, in sorted order, unless columns are set using WithColumns.
+// Keys missing from a record, and nil values, are written as blank cells,
+// leaving the field unchanged; use Null to clear a field. time.Time values
+// are written in RFC 3339 format, for datetime fields; use Date for date
+// fields. The number of columns is not checked by default; if a limit is
+// set using WithMaxColumns, a ColumnLimitError is returned when the file
+// would have more columns.
+func (i *ImportAPI) CreateRecords(ctx context.Context, obj ImportObject, records []map[string]interface{}, opts ...ImportOption) ([]BatchResult, error) {
+	if len(records) == 0 {
+		return nil, errors.New("no records to import")
+	}
+
+	o := &importOptions{params: url.Values{}, nullValue: DefaultNullValue}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	header := o.columns
+	if len(header) == 0 {
+		seen := map[string]bool{}
+		for _, record := range records {
+			for key := range record {
+				if !seen[key] {
+					seen[key] = true
+					header = append(header, key)
+				}
+			}
+		}
+		sort.Strings(header)
+	} else if !o.dropUnknown {
+		listed := map[string]bool{}
+		for _, column := range header {
+			listed[column] = true
+		}
+		for idx, record := range records {
+			for key := range record {
+				if !listed[key] {
+					return nil, fmt.Errorf("record %d: %s is not one of the import columns", idx, key)
+				}
+			}
+		}
+	}
+
+	if o.maxColumns > 0 && len(header) > o.maxColumns {
+		return nil, &ColumnLimitError{Columns: len(header), Limit: o.maxColumns}
+	}
+
+	file, err := encodeRecords(header, records, o.nullValue)
+	if err != nil {
+		return nil, err
+	}
+	return i.Create(ctx, obj, bytes.NewReader(file), opts...)
+}
+
+// encodeRecords returns records encoded as CSV with the provided header,
+// writing Null values as nullValue
+func encodeRecords(header []string, records []map[string]interface{}, nullValue string) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	row := make([]string, len(header))
+	for idx, record := range records {
+		for col, key := range header {
+			if record[key] == Null {
+				row[col] = nullValue
+				continue
+			}
+			value, err := formatValue(record[key])
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %s: %w", idx, key, err)
+			}
+			row[col] = value
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// formatValue returns value formatted for an import file
+func formatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	case Date:
+		return time.Time(v).Format(dateLayout), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case int8, int16, int32, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("unsupported value of type %T", value)
+}
@@ -0,0 +1,160 @@
+package marketo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestImportCreateRecords(t *testing.T) {
+	defer gock.Off()
+
+	var file string
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		MatchParam("format", "csv").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			f, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			body, err := ioutil.ReadAll(f)
+			file = string(body)
+			return err == nil, err
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"requestId":"e42b#14272d07d78","success":true,"result":[{"batchId":1,"status":"Importing"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewImportAPI(client)
+	batches, err := api.CreateRecords(context.Background(), Leads, []map[string]interface{}{
+		{
+			"email":     "nathan@polytomic.com",
+			"score":     json.Number("10"),
+			"updatedAt": time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			"email":     "ghalib@polytomic.com",
+			"firstName": "Ghalib, Jr.",
+			"score":     nil,
+			"active":    true,
+			"updatedAt": Null,
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, "active,email,firstName,score,updatedAt\n"+
+		",nathan@polytomic.com,,10,2021-01-02T03:04:05Z\n"+
+		"true,ghalib@polytomic.com,\"Ghalib, Jr.\",,NULL\n", file)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestImportCreateRecordsDates(t *testing.T) {
+	at := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	file, err := encodeRecords([]string{"externalId", "purchasedAt", "renewsOn"}, []map[string]interface{}{
+		{"externalId": "a1", "purchasedAt": at, "renewsOn": Date(at)},
+	}, DefaultNullValue)
+	require.NoError(t, err)
+	assert.Equal(t, "externalId,purchasedAt,renewsOn\n"+
+		"a1,2021-01-02T03:04:05Z,2021-01-02\n", string(file))
+
+	// the generated file passes validation against the field types
+	problems, err := NewImportAPI(&Client{}).Validate(bytes.NewReader(file), CustomObjectMetadata{
+		APIName:      "testObject_c",
+		DedupeFields: []string{"externalId"},
+		Fields: []ObjectField{
+			{Name: "externalId", DataType: "string"},
+			{Name: "purchasedAt", DataType: "datetime"},
+			{Name: "renewsOn", DataType: "date"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestImportCreateRecordsUnsupported(t *testing.T) {
+	_, err := encodeRecords([]string{"tags"}, []map[string]interface{}{
+		{"tags": []string{"a", "b"}},
+	}, DefaultNullValue)
+	assert.EqualError(t, err, "record 0: tags: unsupported value of type []string")
+}
+
+func TestImportCreateRecordsColumnLimit(t *testing.T) {
+	api := NewImportAPI(&Client{})
+	_, err := api.CreateRecords(context.Background(), Leads, []map[string]interface{}{
+		{"email": "nathan@polytomic.com", "firstName": "Nathan", "lastName": "Yergler"},
+	}, WithMaxColumns(2))
+
+	limitErr := &ColumnLimitError{}
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, 3, limitErr.Columns)
+	assert.Equal(t, 2, limitErr.Limit)
+	assert.EqualError(t, err, "import has 3 columns, exceeding the limit of 2")
+}
+
+func TestImportCreateRecordsColumns(t *testing.T) {
+	defer gock.Off()
+
+	var file string
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			f, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			body, err := ioutil.ReadAll(f)
+			file = string(body)
+			return err == nil, err
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"requestId":"e42b#14272d07d78","success":true,"result":[{"batchId":1,"status":"Importing"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	records := []map[string]interface{}{
+		{"email": "nathan@polytomic.com", "firstName": "Nathan"},
+		{"email": "ghalib@polytomic.com", "score": 10},
+	}
+	api := NewImportAPI(client)
+	_, err = api.CreateRecords(context.Background(), Leads, records,
+		WithColumns("score", "email"),
+	)
+	assert.EqualError(t, err, "record 0: firstName is not one of the import columns")
+
+	_, err = api.CreateRecords(context.Background(), Leads, records,
+		WithColumns("score", "email"),
+		WithDropUnknownColumns(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "score,email\n,nathan@polytomic.com\n10,ghalib@polytomic.com\n", file)
+
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,183 @@
+package marketo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts DefaultRetryPolicy makes
+	// before giving up
+	DefaultMaxAttempts = 3
+	// DefaultRetryBackoff is the delay DefaultRetryPolicy waits after a
+	// rate or concurrency limit error, multiplied by the attempt number
+	DefaultRetryBackoff = 5 * time.Second
+	// MaximumReplayBodySize is the largest request body which is buffered
+	// so that it can be re-sent when retrying; larger bodies which cannot
+	// be re-created are sent once and not retried, and the resulting error
+	// reports NotReplayable. Marketo limits import files to 10MB.
+	MaximumReplayBodySize = 10 << 20
+	// MaximumRetryAfter is the longest delay requested by a Retry-After
+	// header which the client waits before retrying; longer delays are
+	// shortened to it, so that a misconfigured gateway cannot stall calls
+	// which have no deadline
+	MaximumRetryAfter = time.Minute
+)
+
+// RetryPolicy determines whether a REST call should be retried, and how long
+// to wait before doing so. It is called after every attempt with the
+// response or error received and the number of attempts made so far. The
+// body of JSON responses is buffered, so a policy may read it, for example
+// using ResponseReasons, without consuming it. If a retried response includes
+// a Retry-After header, the delay it specifies, up to MaximumRetryAfter, is
+// used instead of the one returned by the policy.
+//
+// Request bodies larger than MaximumReplayBodySize which cannot be
+// re-created by the request's GetBody are sent only once: if the policy
+// asks to retry such a request, the failure is returned with the
+// NotReplayable field of the Error or RequestError set.
+type RetryPolicy func(resp *http.Response, err error, attempt int) (retry bool, delay time.Duration)
+
+// DefaultRetryPolicy retries calls rejected because the access token was
+// invalid or expired, immediately, and calls rejected because a rate or
+// concurrency limit was exceeded, including HTTP 429 and 503 responses from
+// Marketo or a gateway in front of it, with a linear backoff. Transport
+// errors are not retried, since the request may have been processed.
+func DefaultRetryPolicy(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if err != nil || resp == nil || attempt >= DefaultMaxAttempts {
+		return false, 0
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return true, time.Duration(attempt) * DefaultRetryBackoff
+	}
+	for _, r := range ResponseReasons(resp) {
+		switch r.Code {
+		case ErrAccessTokenInvalid.Code, ErrAccessTokenExpired.Code:
+			return true, 0
+		case ErrRateLimitExceeded.Code, ErrConcurrentLimitReached.Code:
+			return true, time.Duration(attempt) * DefaultRetryBackoff
+		}
+	}
+	return false, 0
+}
+
+// ResponseReasons returns the errors included in the body of a JSON
+// response. The body is left unread, so it may still be decoded by the
+// caller.
+func ResponseReasons(resp *http.Response) []Reason {
+	if resp == nil || resp.Body == nil || !isJSON(resp) {
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	response := Response{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+	return response.Errors
+}
+
+// RetryAfter returns the delay requested by the response's Retry-After
+// header, which may be given in seconds or as an HTTP date. It returns false
+// if the header is absent or invalid.
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	return retryAfter(resp, time.Now())
+}
+
+// retryAfter returns the delay requested by the response's Retry-After
+// header, resolving HTTP dates relative to now
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	delay := at.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+// replayable ensures the body of req can be re-sent by setting GetBody,
+// buffering the body if necessary. It returns false if the body exceeds
+// MaximumReplayBodySize, in which case req is left able to send the body
+// once.
+func replayable(req *http.Request) (bool, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return true, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, MaximumReplayBodySize+1))
+	if err != nil {
+		return false, err
+	}
+	if len(body) > MaximumReplayBodySize {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		return false, nil
+	}
+
+	req.Body.Close()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(body))
+	return true, nil
+}
+
+// rewind replaces the body of req with a fresh copy so that it can be sent
+// again
+func rewind(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+func isJSON(resp *http.Response) bool {
+	return strings.Contains(resp.Header.Get("Content-Type"), "json")
+}
+
+// hasReason returns true if any of reasons matches one of targets
+func hasReason(reasons []Reason, targets ...Reason) bool {
+	for _, r := range reasons {
+		for _, t := range targets {
+			if r.Code == t.Code {
+				return true
+			}
+		}
+	}
+	return false
+}
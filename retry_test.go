@@ -0,0 +1,434 @@
+package marketo
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const rateLimitedResponse = `{
+	"requestId": "1001",
+	"success": false,
+	"errors": [{"code": "606", "message": "Max rate limit '100' exceeded with in '20' secs"}]
+}`
+
+func TestRetryPolicy(t *testing.T) {
+	noDelay := func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+		retry, _ := DefaultRetryPolicy(resp, err, attempt)
+		return retry, 0
+	}
+
+	t.Run("retries rate limited calls", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/v1/leads/describe2.json").
+			Reply(http.StatusOK).
+			JSON(rateLimitedResponse)
+		gock.New(testHost).
+			Get("/rest/v1/leads/describe2.json").
+			Reply(http.StatusOK).
+			File("test-fixtures/leads-describe2.json")
+
+		client, err := NewClient(ClientConfig{
+			ID:          clientID,
+			Secret:      clientSecret,
+			Endpoint:    "https://marketo.testing",
+			Debug:       true,
+			RetryPolicy: noDelay,
+		})
+		require.NoError(t, err)
+
+		_, err = NewLeadAPI(client).Describe(context.Background())
+		require.NoError(t, err)
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("refreshes expired tokens", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Times(2).
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/v1/leads/describe2.json").
+			Reply(http.StatusOK).
+			JSON(tokenExpiredResponse)
+		gock.New(testHost).
+			Get("/rest/v1/leads/describe2.json").
+			Reply(http.StatusOK).
+			File("test-fixtures/leads-describe2.json")
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+		})
+		require.NoError(t, err)
+
+		_, err = NewLeadAPI(client).Describe(context.Background())
+		require.NoError(t, err)
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/v1/leads/describe2.json").
+			Times(DefaultMaxAttempts).
+			Reply(http.StatusOK).
+			JSON(rateLimitedResponse)
+
+		client, err := NewClient(ClientConfig{
+			ID:          clientID,
+			Secret:      clientSecret,
+			Endpoint:    "https://marketo.testing",
+			Debug:       true,
+			RetryPolicy: noDelay,
+		})
+		require.NoError(t, err)
+
+		_, err = NewLeadAPI(client).Describe(context.Background())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+		var apiErr Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, DefaultMaxAttempts, apiErr.Attempts)
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("transport errors", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		failure := errors.New("connection reset")
+		gock.New(testHost).
+			Get("/rest/v1/leads/describe2.json").
+			Times(2).
+			ReplyError(failure)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			RetryPolicy: func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+				return err != nil && attempt < 2, 0
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = NewLeadAPI(client).Describe(context.Background())
+		require.Error(t, err)
+		var reqErr *RequestError
+		require.True(t, errors.As(err, &reqErr))
+		assert.Equal(t, 2, reqErr.Attempts)
+		assert.True(t, errors.Is(err, failure))
+
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("custom policy", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New(testHost).
+			Get("/identity/oauth/token").
+			Reply(http.StatusOK).
+			JSON(authResponseSuccess)
+		gock.New(testHost).
+			Get("/rest/v1/leads/describe2.json").
+			Reply(http.StatusOK).
+			JSON(rateLimitedResponse)
+
+		client, err := NewClient(ClientConfig{
+			ID:       clientID,
+			Secret:   clientSecret,
+			Endpoint: "https://marketo.testing",
+			Debug:    true,
+			RetryPolicy: func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+				return false, 0
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = NewLeadAPI(client).Describe(context.Background())
+		assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := RetryAfter(resp)
+	assert.False(t, ok)
+
+	resp.Header.Set("Retry-After", "120")
+	delay, ok := RetryAfter(resp)
+	require.True(t, ok)
+	assert.Equal(t, 2*time.Minute, delay)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	delay, ok = RetryAfter(resp)
+	require.True(t, ok)
+	assert.InDelta(t, time.Hour.Seconds(), delay.Seconds(), 2)
+
+	resp.Header.Set("Retry-After", "soon")
+	_, ok = RetryAfter(resp)
+	assert.False(t, ok)
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusTooManyRequests).
+		SetHeader("Retry-After", "0")
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusOK).
+		File("test-fixtures/leads-describe2.json")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+		RetryPolicy: func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+			retry, _ := DefaultRetryPolicy(resp, err, attempt)
+			return retry, time.Hour
+		},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = NewLeadAPI(client).Describe(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestRetryAfterUsesClientClock(t *testing.T) {
+	defer gock.Off()
+
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusTooManyRequests).
+		SetHeader("Retry-After", "86400")
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusTooManyRequests).
+		// the first retry advanced the clock by MaximumRetryAfter
+		SetHeader("Retry-After", clock.now.Add(MaximumRetryAfter+30*time.Second).Format(http.TimeFormat))
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusOK).
+		File("test-fixtures/leads-describe2.json")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Clock:    clock,
+	})
+	require.NoError(t, err)
+
+	_, err = NewLeadAPI(client).Describe(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{MaximumRetryAfter, 30 * time.Second}, clock.delays)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestReplayable(t *testing.T) {
+	t.Run("buffers bodies", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, testHost, ioutil.NopCloser(strings.NewReader("email\n")))
+		require.NoError(t, err)
+		require.Nil(t, req.GetBody)
+
+		ok, err := replayable(req)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		for i := 0; i < 2; i++ {
+			require.NoError(t, rewind(req))
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "email\n", string(body))
+		}
+	})
+
+	t.Run("large bodies are sent once", func(t *testing.T) {
+		large := strings.Repeat("a", MaximumReplayBodySize+1)
+		req, err := http.NewRequest(http.MethodPost, testHost, ioutil.NopCloser(strings.NewReader(large)))
+		require.NoError(t, err)
+
+		ok, err := replayable(req)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, len(large), len(body))
+	})
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	defer gock.Off()
+
+	bodies := []string{}
+	recordBody := func(r *http.Request, tr *gock.Request) (bool, error) {
+		body, err := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		return true, err
+	}
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(recordBody).
+		Reply(http.StatusOK).
+		JSON(rateLimitedResponse)
+	gock.New(testHost).
+		Post("/rest/v1/customobjects/testObject_c.json").
+		AddMatcher(recordBody).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"seq":0,"status":"created","marketoGUID":"a"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+		RetryPolicy: func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+			retry, _ := DefaultRetryPolicy(resp, err, attempt)
+			return retry, 0
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = NewCustomObjectsAPI(client).Sync(context.Background(), "testObject_c",
+		SyncCreateOrUpdate, "", []map[string]interface{}{{"email": "nathan@polytomic.com"}},
+	)
+	require.NoError(t, err)
+	require.Len(t, bodies, 2)
+	assert.NotEmpty(t, bodies[0])
+	assert.Equal(t, bodies[0], bodies[1])
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestRetryLargeBodyNotReplayable(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/bulk/v1/leads.json").
+		Times(1).
+		Reply(http.StatusOK).
+		JSON(rateLimitedResponse)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		RetryPolicy: func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+			retry, _ := DefaultRetryPolicy(resp, err, attempt)
+			return retry, 0
+		},
+	})
+	require.NoError(t, err)
+
+	large := strings.Repeat("a", MaximumReplayBodySize+1)
+	req, err := http.NewRequest(http.MethodPost, client.endpoint+"/bulk/v1/leads.json", ioutil.NopCloser(strings.NewReader(large)))
+	require.NoError(t, err)
+	_, err = client.doResult(req, "test", nil)
+
+	var e Error
+	require.True(t, errors.As(err, &e), "expected an Error, got %v", err)
+	assert.True(t, e.NotReplayable)
+	assert.Equal(t, 1, e.Attempts)
+	assert.True(t, IsRateLimited(err))
+	assert.True(t, gock.IsDone())
+}
+
+func TestClientStats(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusOK).
+		JSON(rateLimitedResponse)
+	gock.New(testHost).
+		Get("/rest/v1/leads/describe2.json").
+		Reply(http.StatusOK).
+		File("test-fixtures/leads-describe2.json")
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+		RetryPolicy: func(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+			retry, _ := DefaultRetryPolicy(resp, err, attempt)
+			return retry, 0
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = NewLeadAPI(client).Describe(context.Background())
+	require.NoError(t, err)
+
+	stats := client.Stats()
+	assert.Equal(t, int64(2), stats.Requests)
+	assert.Equal(t, int64(1), stats.Retries)
+	assert.Equal(t, int64(1), stats.RateLimited)
+	assert.Equal(t, int64(1), stats.TokenRefreshes)
+	assert.Equal(t, http.StatusOK, stats.LastStatus)
+	assert.False(t, stats.LastRequestAt.IsZero())
+
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,87 @@
+package marketo
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema generated for custom objects
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format,omitempty"`
+	MaxLength            int                    `json:"maxLength,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+}
+
+// FieldByName returns the field with the provided API name
+func (m CustomObjectMetadata) FieldByName(name string) (ObjectField, bool) {
+	for _, field := range m.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return ObjectField{}, false
+}
+
+// FieldByDisplayName returns the field with the provided display name, such
+// as a column header written for people, so that it can be translated to the
+// field's API name. Display names are matched case-insensitively; if more
+// than one field has the name, the first is returned.
+func (m CustomObjectMetadata) FieldByDisplayName(name string) (ObjectField, bool) {
+	for _, field := range m.Fields {
+		if strings.EqualFold(field.DisplayName, name) {
+			return field, true
+		}
+	}
+	return ObjectField{}, false
+}
+
+// JSONSchema returns a JSON Schema describing records of the custom object,
+// suitable for validating records before they are synced or imported. Each
+// field is mapped to the closest JSON type, with its maximum length for
+// string fields, and the dedupe fields are required.
+func (m CustomObjectMetadata) JSONSchema() ([]byte, error) {
+	additional := false
+	schema := jsonSchema{
+		Schema:               "http://json-schema.org/draft-07/schema#",
+		Title:                m.DisplayName,
+		Description:          m.Description,
+		Type:                 "object",
+		Properties:           map[string]*jsonSchema{},
+		Required:             m.DedupeFields,
+		AdditionalProperties: &additional,
+	}
+	for _, field := range m.Fields {
+		property := &jsonSchema{
+			Title: field.DisplayName,
+			Type:  "string",
+		}
+		switch field.DataType {
+		case "integer":
+			property.Type = "integer"
+		case "float", "currency":
+			property.Type = "number"
+		case "boolean":
+			property.Type = "boolean"
+		case "date":
+			property.Format = "date"
+		case "datetime":
+			property.Format = "date-time"
+		case "email":
+			property.Format = "email"
+		case "url":
+			property.Format = "uri"
+		}
+		if property.Type == "string" && property.Format == "" && field.Length > 0 {
+			property.MaxLength = field.Length
+		}
+		schema.Properties[field.Name] = property
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
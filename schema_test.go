@@ -0,0 +1,61 @@
+package marketo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	object := CustomObjectMetadata{
+		APIName:      "testObject_c",
+		DisplayName:  "Test Object",
+		DedupeFields: []string{"email"},
+		Fields: []ObjectField{
+			{Name: "email", DisplayName: "Email", DataType: "email", Length: 255},
+			{Name: "firstName", DisplayName: "First Name", DataType: "string", Length: 36},
+			{Name: "score", DisplayName: "Score", DataType: "integer"},
+			{Name: "amount", DisplayName: "Amount", DataType: "currency"},
+			{Name: "updatedAt", DisplayName: "Updated At", DataType: "datetime"},
+		},
+	}
+
+	schema, err := object.JSONSchema()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title": "Test Object",
+		"type": "object",
+		"properties": {
+			"email": {"title": "Email", "type": "string", "format": "email"},
+			"firstName": {"title": "First Name", "type": "string", "maxLength": 36},
+			"score": {"title": "Score", "type": "integer"},
+			"amount": {"title": "Amount", "type": "number"},
+			"updatedAt": {"title": "Updated At", "type": "string", "format": "date-time"}
+		},
+		"required": ["email"],
+		"additionalProperties": false
+	}`, string(schema))
+}
+
+func TestFieldLookup(t *testing.T) {
+	object := CustomObjectMetadata{
+		Fields: []ObjectField{
+			{Name: "email", DisplayName: "Email Address"},
+			{Name: "firstName", DisplayName: "First Name"},
+		},
+	}
+
+	field, ok := object.FieldByName("firstName")
+	require.True(t, ok)
+	assert.Equal(t, "First Name", field.DisplayName)
+	_, ok = object.FieldByName("First Name")
+	assert.False(t, ok)
+
+	field, ok = object.FieldByDisplayName("email address")
+	require.True(t, ok)
+	assert.Equal(t, "email", field.Name)
+	_, ok = object.FieldByDisplayName("lastName")
+	assert.False(t, ok)
+}
@@ -0,0 +1,85 @@
+package marketo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StaticList is a Marketo static list
+type StaticList struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Folder      FolderReference `json:"folder"`
+	Workspace   string          `json:"workspace"`
+	URL         string          `json:"computedUrl"`
+	CreatedAt   string          `json:"createdAt"`
+	UpdatedAt   string          `json:"updatedAt"`
+}
+
+const (
+	createStaticList = "create static list"
+	deleteStaticList = "delete static list"
+)
+
+// ListsAPI provides access to Marketo static lists
+type ListsAPI struct {
+	c *Client
+}
+
+// NewListsAPI returns a new instance of the lists API, configured with the
+// provided Client.
+func NewListsAPI(c *Client) *ListsAPI {
+	return &ListsAPI{c: c}
+}
+
+// CreateStaticList creates a new static list in the provided folder,
+// returning it
+func (l *ListsAPI) CreateStaticList(ctx context.Context, name string, folderID int) (*StaticList, error) {
+	folder, err := json.Marshal(FolderReference{ID: folderID, Type: FolderTypeFolder})
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("name", name)
+	form.Set("folder", string(folder))
+
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		l.c.url("rest", "asset", "v1", "staticLists.json"),
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	lists := []StaticList{}
+	_, err = l.c.doResult(request, createStaticList, &lists)
+	if err != nil {
+		return nil, err
+	}
+	if len(lists) == 0 {
+		return nil, ErrObjectNotFound
+	}
+	return &lists[0], nil
+}
+
+// DeleteStaticList deletes the static list with the provided ID
+func (l *ListsAPI) DeleteStaticList(ctx context.Context, id int) error {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodPost,
+		l.c.url("rest", "asset", "v1", "staticList", strconv.Itoa(id), "delete.json"),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.c.doResult(request, deleteStaticList, nil)
+	return err
+}
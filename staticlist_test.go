@@ -0,0 +1,52 @@
+package marketo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestStaticListLifecycle(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Post("/rest/asset/v1/staticLists.json").
+		AddMatcher(func(r *http.Request, tr *gock.Request) (bool, error) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "Webinar Attendees", r.PostForm.Get("name"))
+			assert.JSONEq(t, `{"id":1035,"type":"Folder"}`, r.PostForm.Get("folder"))
+			return true, nil
+		}).
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":1189,"name":"Webinar Attendees","folder":{"id":1035,"type":"Folder"}}]}`)
+	gock.New(testHost).
+		Post("/rest/asset/v1/staticList/1189/delete.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[{"id":1189}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewListsAPI(client)
+	list, err := api.CreateStaticList(context.Background(), "Webinar Attendees", 1035)
+	require.NoError(t, err)
+	assert.Equal(t, 1189, list.ID)
+	assert.Equal(t, 1035, list.Folder.ID)
+
+	require.NoError(t, api.DeleteStaticList(context.Background(), list.ID))
+
+	assert.True(t, gock.IsDone())
+}
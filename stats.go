@@ -0,0 +1,87 @@
+package marketo
+
+import (
+	"context"
+	"net/http"
+)
+
+// DailyUsage contains the API calls made on a single day
+type DailyUsage struct {
+	Date  string      `json:"date"`
+	Total int         `json:"total"`
+	Users []UserUsage `json:"users"`
+}
+
+// UserUsage contains the API calls made by a single API user
+type UserUsage struct {
+	UserID string `json:"userId"`
+	Count  int    `json:"count"`
+}
+
+// DailyErrors contains the API errors returned on a single day
+type DailyErrors struct {
+	Date   string       `json:"date"`
+	Total  int          `json:"total"`
+	Errors []ErrorCount `json:"errors"`
+}
+
+// ErrorCount contains the number of times an error code was returned
+type ErrorCount struct {
+	ErrorCode string `json:"errorCode"`
+	Count     int    `json:"count"`
+}
+
+const (
+	getUsage       = "get usage"
+	getErrorCounts = "get error counts"
+)
+
+// StatsAPI provides access to the Marketo API usage statistics
+type StatsAPI struct {
+	c *Client
+}
+
+// NewStatsAPI returns a new instance of the stats API, configured with the
+// provided Client.
+func NewStatsAPI(c *Client) *StatsAPI {
+	return &StatsAPI{c: c}
+}
+
+// Usage returns the number of API calls made today, by API user. Marketo's
+// daily quota is shared by all users of the instance.
+func (s *StatsAPI) Usage(ctx context.Context) ([]DailyUsage, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		s.c.url("rest", "v1", "stats", "usage.json"),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []DailyUsage{}
+	_, err = s.c.doResult(request, getUsage, &usage)
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// Errors returns the number of errors returned today, by error code.
+func (s *StatsAPI) Errors(ctx context.Context) ([]DailyErrors, error) {
+	request, err := http.NewRequestWithContext(ctx,
+		http.MethodGet,
+		s.c.url("rest", "v1", "stats", "errors.json"),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := []DailyErrors{}
+	_, err = s.c.doResult(request, getErrorCounts, &errs)
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
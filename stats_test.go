@@ -0,0 +1,95 @@
+package marketo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestStats(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/stats/usage.json").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"result": [{
+				"date": "2021-06-01",
+				"total": 21,
+				"users": [{"userId": "api@example.com", "count": 21}]
+			}]
+		}`)
+	gock.New(testHost).
+		Get("/rest/v1/stats/errors.json").
+		Reply(http.StatusOK).
+		JSON(`{
+			"success": true,
+			"result": [{
+				"date": "2021-06-01",
+				"total": 4,
+				"errors": [{"errorCode": "606", "count": 4}]
+			}]
+		}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	api := NewStatsAPI(client)
+	usage, err := api.Usage(context.Background())
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, 21, usage[0].Total)
+	assert.Equal(t, "api@example.com", usage[0].Users[0].UserID)
+
+	errs, err := api.Errors(context.Background())
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "606", errs[0].Errors[0].ErrorCode)
+
+	assert.True(t, gock.IsDone())
+}
+
+func TestPing(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testHost).
+		Get("/identity/oauth/token").
+		Reply(http.StatusOK).
+		JSON(authResponseSuccess)
+	gock.New(testHost).
+		Get("/rest/v1/stats/usage.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":true,"result":[]}`)
+	gock.New(testHost).
+		Get("/rest/v1/stats/usage.json").
+		Reply(http.StatusOK).
+		JSON(`{"success":false,"errors":[{"code":"603","message":"Access denied"}]}`)
+
+	client, err := NewClient(ClientConfig{
+		ID:       clientID,
+		Secret:   clientSecret,
+		Endpoint: "https://marketo.testing",
+		Debug:    true,
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, client.Ping(context.Background()))
+	assert.True(t, errors.Is(client.Ping(context.Background()), ErrAccessDenied))
+
+	assert.True(t, gock.IsDone())
+}
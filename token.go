@@ -0,0 +1,71 @@
+package marketo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TokenSource supplies the access tokens used to authenticate REST calls.
+// The client asks for a new token once the previous one has expired, as
+// determined by its ExpiresIn, or when Marketo rejects it; a token which
+// does not specify ExpiresIn is requested again before every call, so
+// sources are expected to cache tokens themselves.
+type TokenSource interface {
+	Token(ctx context.Context) (AuthToken, error)
+}
+
+// TokenSourceFunc adapts a function to the TokenSource interface
+type TokenSourceFunc func(ctx context.Context) (AuthToken, error)
+
+// Token fulfills the TokenSource interface
+func (f TokenSourceFunc) Token(ctx context.Context) (AuthToken, error) {
+	return f(ctx)
+}
+
+// StaticToken returns a TokenSource which always supplies the provided
+// access token, for example one issued by another service. The client
+// cannot refresh the token; once it expires, calls fail with
+// ErrAccessTokenExpired.
+func StaticToken(token string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (AuthToken, error) {
+		return AuthToken{
+			AccessToken: token,
+			TokenType:   "bearer",
+		}, nil
+	})
+}
+
+// ExpiringToken returns a TokenSource which calls token before every REST
+// call for an access token and its expiry. It suits sources which cache and
+// refresh tokens themselves, such as a golang.org/x/oauth2 TokenSource;
+// tokens which expire at or before the time of the call are rejected. To
+// avoid a dependency on golang.org/x/oauth2, an oauth2.TokenSource ts is
+// adapted with a function:
+//
+//	marketo.ExpiringToken(func() (string, time.Time, error) {
+//		t, err := ts.Token()
+//		if err != nil {
+//			return "", time.Time{}, err
+//		}
+//		return t.AccessToken, t.Expiry, nil
+//	})
+//
+// A zero expiry indicates the token does not expire.
+func ExpiringToken(token func() (accessToken string, expiry time.Time, err error)) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (AuthToken, error) {
+		accessToken, expiry, err := token()
+		if err != nil {
+			return AuthToken{}, err
+		}
+		if !expiry.IsZero() && !expiry.After(contextClock(ctx).Now()) {
+			return AuthToken{}, fmt.Errorf("access token expired at %s", expiry)
+		}
+		// ExpiresIn is left unset so that the source is consulted before
+		// every call
+		return AuthToken{
+			AccessToken: accessToken,
+			TokenType:   "bearer",
+		}, nil
+	})
+}
@@ -0,0 +1,231 @@
+package marketo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ImportProblem describes a problem found validating an import file. Row is
+// the 1-based data row the problem was found in, or 0 for the header.
+type ImportProblem struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+// Error fulfills the error interface
+func (p ImportProblem) Error() string {
+	if p.Row == 0 {
+		return fmt.Sprintf("header: %s: %s", p.Column, p.Message)
+	}
+	if p.Column == "" {
+		return fmt.Sprintf("row %d: %s", p.Row, p.Message)
+	}
+	return fmt.Sprintf("row %d: %s: %s", p.Row, p.Column, p.Message)
+}
+
+// importField is a column an import file may contain
+type importField struct {
+	name     string
+	dataType string
+	length   int
+}
+
+// importSchema describes the columns of an import file for an object. It is
+// shared by Create, which checks the header before uploading, and Validate,
+// which checks the whole file.
+type importSchema struct {
+	// object is the name of the object, used in problem descriptions
+	object string
+	// fields are the columns the file may contain, keyed by name; if nil,
+	// any column is accepted and values are not checked
+	fields map[string]importField
+	// dedupe are the columns the file must contain
+	dedupe []string
+	// links are the object's relationships, whose fields the file must
+	// contain if the object is a child
+	links []ObjectRelation
+}
+
+// importSchema returns the schema of import files for the custom object
+func (m CustomObjectMetadata) importSchema() importSchema {
+	schema := importSchema{
+		object: m.APIName,
+		fields: map[string]importField{},
+		dedupe: append([]string{}, m.DedupeFields...),
+		links:  m.Relationships,
+	}
+	for _, f := range m.Fields {
+		schema.fields[f.Name] = importField{name: f.Name, dataType: f.DataType, length: f.Length}
+	}
+	return schema
+}
+
+// importSchema returns the schema of lead import files. Leads are
+// deduplicated using lookupField when it is set, as by WithLookupField, and
+// the lead's dedupe fields otherwise.
+func (m LeadMetadata) importSchema(lookupField string) importSchema {
+	schema := importSchema{
+		object: "leads",
+		fields: map[string]importField{},
+		dedupe: append([]string{}, m.DedupeFields...),
+	}
+	if lookupField != "" {
+		schema.dedupe = []string{lookupField}
+	}
+	for _, f := range m.Fields {
+		schema.fields[f.Name] = importField{name: f.Name, dataType: f.DataType, length: f.Length}
+	}
+	return schema
+}
+
+// checkHeader returns the problems with an import file's header: columns
+// which are not fields of the object, and missing dedupe and link columns
+func (s importSchema) checkHeader(header []string) []ImportProblem {
+	problems := []ImportProblem{}
+	if s.fields != nil {
+		for _, column := range header {
+			if _, ok := s.fields[column]; !ok {
+				problems = append(problems, ImportProblem{
+					Column:  column,
+					Message: "not a field of " + s.object,
+				})
+			}
+		}
+	}
+	for _, column := range missing(s.dedupe, header) {
+		problems = append(problems, ImportProblem{
+			Column:  column,
+			Message: "missing dedupe column",
+		})
+	}
+	for _, r := range s.links {
+		if r.Type == RelationChild && !contains(header, r.Field) && !contains(s.dedupe, r.Field) {
+			problems = append(problems, ImportProblem{
+				Column:  r.Field,
+				Message: fmt.Sprintf("missing link column to %s, containing its %s", r.RelatedTo.Name, r.RelatedTo.Field),
+			})
+		}
+	}
+	return problems
+}
+
+// checkRow returns the problems with the values of a data row, numbered
+// from 1, of an import file with the provided header
+func (s importSchema) checkRow(row int, header, record []string) []ImportProblem {
+	if len(record) != len(header) {
+		return []ImportProblem{{
+			Row:     row,
+			Message: fmt.Sprintf("expected %d columns, got %d", len(header), len(record)),
+		}}
+	}
+	problems := []ImportProblem{}
+	for idx, value := range record {
+		field, ok := s.fields[header[idx]]
+		if !ok || value == "" {
+			continue
+		}
+		if msg := validateValue(field, value); msg != "" {
+			problems = append(problems, ImportProblem{
+				Row:     row,
+				Column:  field.name,
+				Message: msg,
+			})
+		}
+	}
+	return problems
+}
+
+// Validate checks an import file against the custom object's schema without
+// submitting it, returning the problems found. The header must contain only
+// fields of the object, along with its dedupe fields, its link fields, and
+// any columns required with WithDedupeColumns; each value must fit its
+// field's data type and length. An error is returned only if the file cannot
+// be read.
+func (i *ImportAPI) Validate(file io.Reader, object CustomObjectMetadata, opts ...ImportOption) ([]ImportProblem, error) {
+	o := &importOptions{params: url.Values{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return validate(file, object.importSchema(), o)
+}
+
+// ValidateLeads checks a lead import file against the lead schema returned
+// by LeadAPI.Describe, as Validate does for custom objects. The header must
+// contain the lookup field set using WithLookupField, or the lead's dedupe
+// fields if none is set.
+func (i *ImportAPI) ValidateLeads(file io.Reader, lead LeadMetadata, opts ...ImportOption) ([]ImportProblem, error) {
+	o := &importOptions{params: url.Values{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return validate(file, lead.importSchema(o.params.Get("lookupField")), o)
+}
+
+// validate checks the header and each row of file against schema
+func validate(file io.Reader, schema importSchema, o *importOptions) ([]ImportProblem, error) {
+	schema.dedupe = append(schema.dedupe, missing(o.dedupeColumns, schema.dedupe)...)
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read import header: %w", err)
+	}
+
+	problems := schema.checkHeader(header)
+	row := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row++
+		problems = append(problems, schema.checkRow(row, header, record)...)
+	}
+
+	return problems, nil
+}
+
+// validateValue returns a description of the problem with value for the
+// provided field, or an empty string if it is valid
+func validateValue(field importField, value string) string {
+	var err error
+	switch field.dataType {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "float", "currency":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	case "date":
+		_, err = time.Parse(dateLayout, value)
+	case "datetime":
+		_, err = time.Parse(time.RFC3339, value)
+	default:
+		if field.length > 0 && len([]rune(value)) > field.length {
+			return fmt.Sprintf("value exceeds maximum length of %d", field.length)
+		}
+	}
+	if err != nil {
+		return fmt.Sprintf("invalid %s value %q", field.dataType, value)
+	}
+	return ""
+}
+
+// contains returns true if values contains v
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
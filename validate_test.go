@@ -0,0 +1,124 @@
+package marketo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportValidate(t *testing.T) {
+	object := CustomObjectMetadata{
+		APIName:      "testObject_c",
+		DedupeFields: []string{"externalId"},
+		Fields: []ObjectField{
+			{Name: "externalId", DataType: "string", Length: 5},
+			{Name: "score", DataType: "integer"},
+			{Name: "purchasedAt", DataType: "datetime"},
+		},
+	}
+	api := NewImportAPI(&Client{})
+
+	t.Run("valid", func(t *testing.T) {
+		problems, err := api.Validate(strings.NewReader(
+			"externalId,score,purchasedAt\n"+
+				"a1,10,2021-06-01T10:00:00Z\n"+
+				"a2,,\n",
+		), object)
+		require.NoError(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		problems, err := api.Validate(strings.NewReader(
+			"score,color\n"+
+				"ten,red\n"+
+				"10\n",
+		), object)
+		require.NoError(t, err)
+
+		msgs := make([]string, len(problems))
+		for i, p := range problems {
+			msgs[i] = p.Error()
+		}
+		assert.Equal(t, []string{
+			"header: color: not a field of testObject_c",
+			"header: externalId: missing dedupe column",
+			`row 1: score: invalid integer value "ten"`,
+			"row 2: expected 2 columns, got 1",
+		}, msgs)
+	})
+
+	t.Run("link", func(t *testing.T) {
+		child := object
+		child.Fields = append(child.Fields, ObjectField{Name: "leadEmail", DataType: "link"})
+		child.Relationships = []ObjectRelation{{
+			Field:     "leadEmail",
+			RelatedTo: RelatedObject{Name: "Lead", Field: "email"},
+			Type:      RelationChild,
+		}}
+		assert.Equal(t, []string{"externalId", "leadEmail"}, child.RequiredImportColumns())
+
+		problems, err := api.Validate(strings.NewReader(
+			"externalId\n"+
+				"a1\n",
+		), child)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		assert.Equal(t, "header: leadEmail: missing link column to Lead, containing its email", problems[0].Error())
+	})
+
+	t.Run("length", func(t *testing.T) {
+		problems, err := api.Validate(strings.NewReader(
+			"externalId\n"+
+				"abcdef\n",
+		), object)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		assert.Equal(t, "value exceeds maximum length of 5", problems[0].Message)
+	})
+}
+
+func TestImportValidateLeads(t *testing.T) {
+	lead := LeadMetadata{
+		Name:         "Lead",
+		DedupeFields: []string{"email"},
+		Fields: []LeadAttribute2{
+			{Name: "id", DataType: "integer"},
+			{Name: "email", DataType: "email", Length: 255},
+			{Name: "leadScore", DataType: "integer"},
+		},
+	}
+	api := NewImportAPI(&Client{})
+
+	problems, err := api.ValidateLeads(strings.NewReader(
+		"email,leadScore,color\n"+
+			"nathan@polytomic.com,high,red\n",
+	), lead)
+	require.NoError(t, err)
+	msgs := make([]string, len(problems))
+	for i, p := range problems {
+		msgs[i] = p.Error()
+	}
+	assert.Equal(t, []string{
+		"header: color: not a field of leads",
+		`row 1: leadScore: invalid integer value "high"`,
+	}, msgs)
+
+	// the lookup field replaces the dedupe fields
+	problems, err = api.ValidateLeads(strings.NewReader(
+		"id,leadScore\n"+
+			"1,10\n",
+	), lead, WithLookupField("id"))
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	problems, err = api.ValidateLeads(strings.NewReader(
+		"leadScore\n"+
+			"10\n",
+	), lead)
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Equal(t, "header: email: missing dedupe column", problems[0].Error())
+}